@@ -0,0 +1,136 @@
+// Package auth authenticates the players of online mode servers with a
+// session service.
+// See https://wiki.vg/Protocol_Encryption#Authentication for more info.
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+const (
+	// DefaultBaseURL is the URL of the Mojang session service.
+	DefaultBaseURL = "https://sessionserver.mojang.com"
+	// DefaultTimeout is the time given to the requests of a SessionService
+	// without Client.
+	DefaultTimeout = 10 * time.Second
+)
+
+var defaultClient = &http.Client{Timeout: DefaultTimeout}
+
+// ErrNotJoined is returned when the player did not join the server.
+var ErrNotJoined = errors.New("auth: player has not joined the server")
+
+// Profile is the profile of an authenticated player.
+type Profile struct {
+	ID         uuid.UUID
+	Name       string
+	Properties []Property
+}
+
+// Property is a property of a Profile, for example the "textures" of the
+// player's skin.
+type Property struct {
+	Name      string `json:"name"`
+	Value     string `json:"value"`
+	Signature string `json:"signature,omitempty"`
+}
+
+// profileJSON is a Profile as sent by the session service, with an ID
+// without dashes.
+type profileJSON struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Properties []Property `json:"properties,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (p Profile) MarshalJSON() ([]byte, error) {
+	return json.Marshal(profileJSON{
+		ID:         fmt.Sprintf("%x", p.ID.Bytes()),
+		Name:       p.Name,
+		Properties: p.Properties,
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (p *Profile) UnmarshalJSON(b []byte) error {
+	var v profileJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	id, err := uuid.FromString(v.ID)
+	if err != nil {
+		return fmt.Errorf("auth: invalid profile ID %q", v.ID)
+	}
+
+	*p = Profile{ID: id, Name: v.Name, Properties: v.Properties}
+	return nil
+}
+
+// SessionVerifier verifies that a player logging in joined the server with
+// the session service.
+type SessionVerifier interface {
+	// HasJoined returns the profile of the player named username if it joined
+	// the server identified by serverHash, ErrNotJoined otherwise. It gives
+	// up once ctx is done.
+	HasJoined(ctx context.Context, username, serverHash string) (*Profile, error)
+}
+
+// SessionService is a SessionVerifier using the HTTP API of a session
+// service.
+type SessionService struct {
+	// BaseURL is the URL of the session service, DefaultBaseURL when empty.
+	BaseURL string
+	// Client makes the requests, a client with a timeout of DefaultTimeout
+	// when nil.
+	Client *http.Client
+}
+
+// HasJoined implements the SessionVerifier interface.
+func (s *SessionService) HasJoined(ctx context.Context, username, serverHash string) (*Profile, error) {
+	baseURL := s.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	client := s.Client
+	if client == nil {
+		client = defaultClient
+	}
+
+	query := url.Values{
+		"username": {username},
+		"serverId": {serverHash},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/session/minecraft/hasJoined?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNoContent:
+		return nil, ErrNotJoined
+	default:
+		return nil, fmt.Errorf("auth: session service returned %s", resp.Status)
+	}
+
+	p := &Profile{}
+	if err := json.NewDecoder(resp.Body).Decode(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
@@ -0,0 +1,53 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/JDWardle/gocraft/auth"
+	"github.com/JDWardle/gocraft/auth/authtest"
+	"github.com/gofrs/uuid"
+)
+
+func TestSessionService(t *testing.T) {
+	s := authtest.NewServer()
+	defer s.Close()
+
+	profile := auth.Profile{
+		ID:         uuid.Must(uuid.FromString("069a79f4-44e9-4726-a5be-fca90e38aaf5")),
+		Name:       "Notch",
+		Properties: []auth.Property{{Name: "textures", Value: "e30=", Signature: "c2ln"}},
+	}
+	s.Join(profile, "-7c9d5b0044c130109a5d7b5fb5c317c02b4e28c1")
+
+	got, err := s.SessionService().HasJoined(context.Background(), "Notch", "-7c9d5b0044c130109a5d7b5fb5c317c02b4e28c1")
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	if !reflect.DeepEqual(*got, profile) {
+		t.Fatalf("Expected '%+v' got '%+v'", profile, *got)
+	}
+
+	if _, err := s.SessionService().HasJoined(context.Background(), "Notch", "4ed1f46bbe04bc756bcb17c0c7ce3e4632f06a48"); err != auth.ErrNotJoined {
+		t.Fatalf("Expected '%v' got '%v'", auth.ErrNotJoined, err)
+	}
+}
+
+func TestSessionServiceCancel(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer s.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	service := &auth.SessionService{BaseURL: s.URL}
+	if _, err := service.HasJoined(ctx, "Notch", "hash"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected '%v' got '%v'", context.DeadlineExceeded, err)
+	}
+}
@@ -0,0 +1,67 @@
+// Package authtest provides a local session service to test online mode
+// logins without network access.
+package authtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/JDWardle/gocraft/auth"
+)
+
+// Server is a session service listening on a local address. Players join it
+// with Join, as a client does before sending its Encryption Response.
+type Server struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	joined map[session]auth.Profile
+}
+
+type session struct {
+	username, serverHash string
+}
+
+// NewServer starts and returns a new Server. It should be closed when
+// finished.
+func NewServer() *Server {
+	s := &Server{joined: make(map[session]auth.Profile)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/session/minecraft/hasJoined", s.hasJoined)
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// Join records that the player of profile joined the server identified by
+// serverHash.
+func (s *Server) Join(profile auth.Profile, serverHash string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.joined[session{profile.Name, serverHash}] = profile
+}
+
+// SessionService returns a SessionService using the server.
+func (s *Server) SessionService() *auth.SessionService {
+	return &auth.SessionService{BaseURL: s.URL, Client: s.Client()}
+}
+
+func (s *Server) hasJoined(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	s.mu.Lock()
+	profile, ok := s.joined[session{query.Get("username"), query.Get("serverId")}]
+	s.mu.Unlock()
+
+	if !ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(profile)
+}
@@ -0,0 +1,28 @@
+package auth
+
+import (
+	"crypto/sha1"
+	"math/big"
+)
+
+// ServerHash returns the hash identifying the server to the session service.
+// It is the HexDigest of the SHA-1 of the server ID, the shared secret and the
+// encoded public key sent in the Encryption Request.
+func ServerHash(serverID string, sharedSecret, publicKey []byte) string {
+	h := sha1.New()
+	h.Write([]byte(serverID))
+	h.Write(sharedSecret)
+	h.Write(publicKey)
+	return HexDigest(h.Sum(nil))
+}
+
+// HexDigest returns the hexadecimal representation of digest as a two's
+// complement signed number, as done by Java's BigInteger.toString(16). The
+// result has no leading zeros and is prefixed by "-" when negative.
+func HexDigest(digest []byte) string {
+	n := new(big.Int).SetBytes(digest)
+	if len(digest) > 0 && digest[0]&0x80 != 0 {
+		n.Sub(n, new(big.Int).Lsh(big.NewInt(1), uint(len(digest))*8))
+	}
+	return n.Text(16)
+}
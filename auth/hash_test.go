@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"crypto/sha1"
+	"testing"
+)
+
+func TestHexDigest(t *testing.T) {
+	tests := map[string]string{
+		"Notch": "4ed1f46bbe04bc756bcb17c0c7ce3e4632f06a48",
+		"jeb_":  "-7c9d5b0044c130109a5d7b5fb5c317c02b4e28c1",
+		"simon": "88e16a1019277b15d58faf0541e11910eb756f6",
+	}
+
+	for test, expected := range tests {
+		sum := sha1.Sum([]byte(test))
+		if got := HexDigest(sum[:]); got != expected {
+			t.Fatalf("Expected '%s' to be digested as '%s' got '%s'", test, expected, got)
+		}
+	}
+}
+
+func TestServerHash(t *testing.T) {
+	if got := ServerHash("Not", []byte("c"), []byte("h")); got != "4ed1f46bbe04bc756bcb17c0c7ce3e4632f06a48" {
+		t.Fatalf("Expected '%s' got '%s'", "4ed1f46bbe04bc756bcb17c0c7ce3e4632f06a48", got)
+	}
+}
@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"crypto/md5"
+
+	"github.com/gofrs/uuid"
+)
+
+// MaxUsernameLength is the maximum length of a username.
+const MaxUsernameLength = 16
+
+// OfflineUUID returns the UUID of the player named username on offline mode
+// servers. It is the UUID version 3 of "OfflinePlayer:<username>" without a
+// namespace, as generated by Java's UUID.nameUUIDFromBytes.
+func OfflineUUID(username string) uuid.UUID {
+	var u uuid.UUID
+	sum := md5.Sum([]byte("OfflinePlayer:" + username))
+	copy(u[:], sum[:])
+
+	u.SetVersion(uuid.V3)
+	u.SetVariant(uuid.VariantRFC4122)
+	return u
+}
+
+// OfflineProfile returns the profile of the player named username on offline
+// mode servers.
+func OfflineProfile(username string) *Profile {
+	return &Profile{ID: OfflineUUID(username), Name: username}
+}
+
+// ValidUsername reports whether username is between 1 and MaxUsernameLength
+// characters long and only contains letters, digits and underscores.
+func ValidUsername(username string) bool {
+	if len(username) == 0 || len(username) > MaxUsernameLength {
+		return false
+	}
+
+	for _, c := range username {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,34 @@
+package auth
+
+import "testing"
+
+func TestOfflineUUID(t *testing.T) {
+	tests := map[string]string{
+		"Notch": "b50ad385-829d-3141-a216-7e7d7539ba7f",
+		"jeb_":  "a762f560-4fce-3236-812a-b80efff0b62b",
+	}
+
+	for test, expected := range tests {
+		if got := OfflineUUID(test).String(); got != expected {
+			t.Fatalf("Expected '%s' got '%s'", expected, got)
+		}
+	}
+}
+
+func TestValidUsername(t *testing.T) {
+	tests := map[string]bool{
+		"Notch":             true,
+		"jeb_":              true,
+		"":                  false,
+		"Not ch":            false,
+		"é":                 false,
+		"ThisNameIsTooLong": false,
+		"ThisNameIsLong16":  true,
+	}
+
+	for test, expected := range tests {
+		if got := ValidUsername(test); got != expected {
+			t.Fatalf("Expected '%s' to be valid: %t got %t", test, expected, got)
+		}
+	}
+}
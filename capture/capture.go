@@ -0,0 +1,206 @@
+// Package capture records the packets exchanged with clients to files which
+// can be decoded and replayed, see cmd/mcreplay.
+//
+// A capture starts with the magic "MCCAP" and a format version byte, followed
+// by the records. Each record is:
+//
+//	timestamp   int64, Unix nanoseconds, big endian
+//	direction   byte, 0 for serverbound and 1 for clientbound
+//	state       VarInt
+//	protocol    VarInt, the protocol version of the client
+//	packet ID   VarInt
+//	length      VarInt
+//	data        length bytes, the packet after its ID
+//
+// The packets are recorded decrypted and uncompressed.
+package capture
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/JDWardle/gocraft/protocol"
+)
+
+// Magic starts every capture.
+const Magic = "MCCAP"
+
+// formatVersion is the version of the format of the records.
+const formatVersion = 1
+
+// ErrInvalidCapture is returned when reading something else than a capture.
+var ErrInvalidCapture = errors.New("capture: invalid capture")
+
+// Direction is the direction of a recorded packet.
+type Direction byte
+
+const (
+	// Serverbound packets are sent by the client.
+	Serverbound Direction = iota
+	// Clientbound packets are sent by the server.
+	Clientbound
+)
+
+func (d Direction) String() string {
+	switch d {
+	case Serverbound:
+		return "serverbound"
+	case Clientbound:
+		return "clientbound"
+	}
+	return fmt.Sprintf("Direction(%d)", byte(d))
+}
+
+// Record is a recorded packet.
+type Record struct {
+	Time      time.Time
+	Direction Direction
+	State     protocol.ClientState
+	// Protocol is the protocol version of the client, e.g. 404.
+	Protocol int32
+	ID       int32
+	Data     []byte
+}
+
+// Packet decodes the packet with the packets registry of its protocol
+// version.
+func (r *Record) Packet() (protocol.Handler, error) {
+	version, ok := protocol.SupportedVersions.Get(r.Protocol)
+	if !ok {
+		return nil, fmt.Errorf("capture: unsupported protocol version %d", r.Protocol)
+	}
+
+	packets := version.ServerPackets
+	if r.Direction == Clientbound {
+		packets = version.ClientPackets
+	}
+
+	ok, p := packets.GetPacket(r.State, r.ID)
+	if !ok {
+		return nil, fmt.Errorf("capture: unknown %s packet %#02x of state %d", r.Direction, r.ID, r.State)
+	}
+	if err := p.Decode(bufio.NewReader(bytes.NewReader(r.Data))); err != nil {
+		return nil, fmt.Errorf("capture: invalid %T: %v", p, err)
+	}
+	return p, nil
+}
+
+// Writer writes records to a capture. It is safe to use from several
+// goroutines.
+type Writer struct {
+	w  io.Writer
+	mu sync.Mutex
+}
+
+// NewWriter writes the header of a capture to w and returns a Writer of its
+// records.
+func NewWriter(w io.Writer) (*Writer, error) {
+	if _, err := w.Write(append([]byte(Magic), formatVersion)); err != nil {
+		return nil, err
+	}
+	return &Writer{w: w}, nil
+}
+
+// Write writes the record r in a single write.
+func (w *Writer) Write(r *Record) error {
+	var b bytes.Buffer
+	binary.Write(&b, binary.BigEndian, r.Time.UnixNano())
+	b.WriteByte(byte(r.Direction))
+	b.Write(protocol.VarInt(int32(r.State)))
+	b.Write(protocol.VarInt(r.Protocol))
+	b.Write(protocol.VarInt(r.ID))
+	b.Write(protocol.VarInt(int32(len(r.Data))))
+	b.Write(r.Data)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, err := w.w.Write(b.Bytes())
+	return err
+}
+
+// Close closes the underlying writer when it is an io.Closer.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if c, ok := w.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Reader reads the records of a capture.
+type Reader struct {
+	r *bufio.Reader
+}
+
+// NewReader reads the header of the capture of r and returns a Reader of its
+// records.
+func NewReader(r io.Reader) (*Reader, error) {
+	br := bufio.NewReader(r)
+
+	header := make([]byte, len(Magic)+1)
+	if _, err := io.ReadFull(br, header); err != nil || string(header[:len(Magic)]) != Magic {
+		return nil, ErrInvalidCapture
+	}
+	if header[len(Magic)] != formatVersion {
+		return nil, fmt.Errorf("capture: unsupported format version %d", header[len(Magic)])
+	}
+	return &Reader{r: br}, nil
+}
+
+// Read returns the next record, or io.EOF at the end of the capture.
+func (r *Reader) Read() (*Record, error) {
+	var nanos int64
+	if err := binary.Read(r.r, binary.BigEndian, &nanos); err != nil {
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		return nil, truncated(err)
+	}
+
+	direction, err := r.r.ReadByte()
+	if err != nil {
+		return nil, truncated(err)
+	}
+
+	var fields [4]int32
+	for i := range fields {
+		if fields[i], err = protocol.ReadVarInt(r.r); err != nil {
+			return nil, truncated(err)
+		}
+	}
+	length := fields[3]
+	if length < 0 || length > protocol.MaxPacketLength {
+		return nil, fmt.Errorf("capture: invalid packet length %d", length)
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r.r, data); err != nil {
+		return nil, truncated(err)
+	}
+
+	return &Record{
+		Time:      time.Unix(0, nanos),
+		Direction: Direction(direction),
+		State:     protocol.ClientState(fields[0]),
+		Protocol:  fields[1],
+		ID:        fields[2],
+		Data:      data,
+	}, nil
+}
+
+// truncated returns the error of a record cut short.
+func truncated(err error) error {
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return fmt.Errorf("capture: truncated record: %v", err)
+}
@@ -0,0 +1,95 @@
+package capture
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/JDWardle/gocraft/protocol"
+	"github.com/JDWardle/gocraft/protocol/client"
+	"github.com/JDWardle/gocraft/protocol/server"
+)
+
+func TestCapture(t *testing.T) {
+	start := time.Unix(0, 1546300800000000000)
+	records := []*Record{
+		{Time: start, Direction: Serverbound, State: protocol.ClientStateLogin, Protocol: 404, ID: int32(server.LoginStart), Data: append([]byte{0x05}, "Notch"...)},
+		{Time: start.Add(time.Millisecond), Direction: Clientbound, State: protocol.ClientStateLogin, Protocol: 404, ID: int32(client.SetCompression), Data: []byte{0x80, 0x02}},
+		{Time: start.Add(time.Second), Direction: Clientbound, State: protocol.ClientStatePlay, Protocol: 404, ID: int32(client.KeepAlive), Data: make([]byte, 8)},
+	}
+
+	var b bytes.Buffer
+	w, err := NewWriter(&b)
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	for _, record := range records {
+		if err := w.Write(record); err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+	}
+
+	r, err := NewReader(&b)
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	for _, expected := range records {
+		record, err := r.Read()
+		if err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+		if !reflect.DeepEqual(record, expected) {
+			t.Fatalf("Expected '%+v' got '%+v'", expected, record)
+		}
+	}
+	if _, err := r.Read(); err != io.EOF {
+		t.Fatalf("Expected '%v' got '%v'", io.EOF, err)
+	}
+}
+
+func TestRecordPacket(t *testing.T) {
+	tests := map[*Record]protocol.Handler{
+		{Direction: Serverbound, State: protocol.ClientStateLogin, Protocol: 404, ID: int32(server.LoginStart), Data: append([]byte{0x05}, "Notch"...)}: &protocol.LoginStart{Name: "Notch"},
+		{Direction: Clientbound, State: protocol.ClientStateLogin, Protocol: 404, ID: int32(client.SetCompression), Data: []byte{0x80, 0x02}}:           &protocol.SetCompression{Threshold: 256},
+		{Direction: Clientbound, State: protocol.ClientStatePlay, Protocol: 404, ID: 0x7f}:                                                              nil,
+		{Direction: Serverbound, State: protocol.ClientStateLogin, Protocol: 1, ID: int32(server.LoginStart)}:                                           nil,
+		{Direction: Serverbound, State: protocol.ClientStateLogin, Protocol: 404, ID: int32(server.LoginStart), Data: []byte{0x05}}:                     nil,
+	}
+
+	for record, expected := range tests {
+		packet, err := record.Packet()
+		if expected == nil {
+			if err == nil {
+				t.Fatalf("Expected an error decoding '%+v' got '%+v'", record, packet)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(packet, expected) {
+			t.Fatalf("Expected '%+v' got '%+v' '%v'", expected, packet, err)
+		}
+	}
+}
+
+func TestInvalidCapture(t *testing.T) {
+	tests := map[string][]byte{
+		"empty":          nil,
+		"magic":          []byte("MCCAX\x01"),
+		"format version": []byte("MCCAP\x02"),
+	}
+
+	for name, test := range tests {
+		if _, err := NewReader(bytes.NewReader(test)); err == nil {
+			t.Fatalf("Expected an error for an invalid %s", name)
+		}
+	}
+
+	r, err := NewReader(bytes.NewReader([]byte("MCCAP\x01\x00\x00\x00")))
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	if _, err := r.Read(); err == nil || err == io.EOF {
+		t.Fatalf("Expected an error for a truncated record got '%v'", err)
+	}
+}
@@ -0,0 +1,122 @@
+// Command mcreplay decodes the packet captures recorded by the server and
+// replays them against a server.
+//
+// Usage:
+//
+//	mcreplay dump FILE
+//	mcreplay replay [-addr host:port] [-timeout 5s] [-strict] FILE
+//
+// dump prints every packet of the capture. replay connects to the server,
+// sends the serverbound packets of the capture and exits with status 1 when
+// the server does not answer with the recorded packets, which makes captures
+// usable as regression tests. Captures of online mode servers cannot be
+// replayed since the encryption keys change on every connection.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"time"
+
+	"github.com/JDWardle/gocraft/capture"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "dump":
+		if len(os.Args) != 3 {
+			usage()
+		}
+		err = dumpFile(os.Stdout, os.Args[2])
+	case "replay":
+		flags := flag.NewFlagSet("replay", flag.ExitOnError)
+		addr := flags.String("addr", "localhost:25565", "address of the server")
+		timeout := flags.Duration("timeout", 5*time.Second, "time given to the server to send each packet")
+		strict := flags.Bool("strict", false, "compare the data of the packets in addition to their ID")
+		flags.Parse(os.Args[2:])
+		if flags.NArg() != 1 {
+			usage()
+		}
+		err = replayFile(*addr, flags.Arg(0), *timeout, *strict)
+	default:
+		usage()
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "mcreplay:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: mcreplay dump FILE")
+	fmt.Fprintln(os.Stderr, "       mcreplay replay [-addr host:port] [-timeout 5s] [-strict] FILE")
+	os.Exit(2)
+}
+
+func dumpFile(w io.Writer, name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	captures, err := capture.NewReader(f)
+	if err != nil {
+		return err
+	}
+	return dump(w, captures)
+}
+
+// dump prints the records of the capture, one per line, with the time since
+// the first record.
+func dump(w io.Writer, captures *capture.Reader) error {
+	var start time.Time
+	for {
+		record, err := captures.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if start.IsZero() {
+			start = record.Time
+		}
+
+		arrow := "->"
+		if record.Direction == capture.Clientbound {
+			arrow = "<-"
+		}
+		fmt.Fprintf(w, "%10.3fs %s state=%d id=%#02x %s\n", record.Time.Sub(start).Seconds(), arrow, record.State, record.ID, describe(record))
+	}
+}
+
+func replayFile(addr, name string, timeout time.Duration, strict bool) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	captures, err := capture.NewReader(f)
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return newReplayer(conn, timeout, strict).replay(captures)
+}
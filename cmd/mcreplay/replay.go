@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/JDWardle/gocraft/capture"
+	"github.com/JDWardle/gocraft/protocol"
+)
+
+// replayer plays the client side of a capture against a server and checks
+// that the server answers with the recorded packets.
+type replayer struct {
+	conn net.Conn
+	r    *protocol.FrameReader
+	w    *protocol.FrameWriter
+	// timeout is the time given to the server to send each packet.
+	timeout time.Duration
+	// strict compares the data of the packets in addition to their ID.
+	strict bool
+}
+
+func newReplayer(conn net.Conn, timeout time.Duration, strict bool) *replayer {
+	return &replayer{
+		conn:    conn,
+		r:       protocol.NewFrameReader(conn),
+		w:       protocol.NewFrameWriter(conn),
+		timeout: timeout,
+		strict:  strict,
+	}
+}
+
+// replay sends the serverbound packets of the capture in order, reading the
+// clientbound packets recorded before each of them first. Keep Alive is
+// answered as it comes since its timing and IDs change on every run.
+func (p *replayer) replay(captures *capture.Reader) error {
+	for n := 1; ; n++ {
+		record, err := captures.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		version, ok := protocol.SupportedVersions.Get(record.Protocol)
+		if !ok {
+			return fmt.Errorf("record %d: unsupported protocol version %d", n, record.Protocol)
+		}
+		if isKeepAlive(version, record) {
+			continue
+		}
+
+		if record.Direction == capture.Serverbound {
+			if err := p.w.WriteFrame(record.ID, record.Data); err != nil {
+				return fmt.Errorf("record %d: %v", n, err)
+			}
+			continue
+		}
+
+		if id, _ := version.ClientPackets.ID(protocol.ClientStateLogin, &protocol.EncryptionRequest{}); record.State == protocol.ClientStateLogin && record.ID == id {
+			return fmt.Errorf("record %d: encrypted sessions cannot be replayed, record an offline mode server", n)
+		}
+		if err := p.expect(version, record); err != nil {
+			return fmt.Errorf("record %d: %v", n, err)
+		}
+	}
+}
+
+// expect reads the next packet of the server, other than Keep Alive, and
+// checks that it is the recorded packet.
+func (p *replayer) expect(version *protocol.Version, record *capture.Record) error {
+	for {
+		p.conn.SetReadDeadline(time.Now().Add(p.timeout))
+		frame, err := p.r.ReadFrame()
+		if err != nil {
+			return fmt.Errorf("expected packet %#02x of state %d: %v", record.ID, record.State, err)
+		}
+
+		received := &capture.Record{Direction: capture.Clientbound, State: record.State, Protocol: record.Protocol, ID: frame.ID, Data: frame.Data}
+		if isKeepAlive(version, received) {
+			id, _ := version.ServerPackets.ID(protocol.ClientStatePlay, &protocol.KeepAlive{})
+			if err := p.w.WriteFrame(id, frame.Data); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if frame.ID != record.ID {
+			return fmt.Errorf("expected %s got %s", describe(record), describe(received))
+		}
+		if p.strict && !bytes.Equal(frame.Data, record.Data) {
+			return fmt.Errorf("expected %s got %s", describe(record), describe(received))
+		}
+
+		if id, _ := version.ClientPackets.ID(protocol.ClientStateLogin, &protocol.SetCompression{}); record.State == protocol.ClientStateLogin && record.ID == id {
+			packet, err := received.Packet()
+			if err != nil {
+				return err
+			}
+			threshold := int(packet.(*protocol.SetCompression).Threshold)
+			p.r.SetCompression(threshold)
+			p.w.SetCompression(threshold)
+		}
+		return nil
+	}
+}
+
+// isKeepAlive returns whether the record is a Keep Alive of either direction.
+func isKeepAlive(version *protocol.Version, record *capture.Record) bool {
+	if record.State != protocol.ClientStatePlay {
+		return false
+	}
+
+	var id int32
+	if record.Direction == capture.Serverbound {
+		id, _ = version.ServerPackets.ID(protocol.ClientStatePlay, &protocol.KeepAlive{})
+	} else {
+		id, _ = version.ClientPackets.ID(protocol.ClientStatePlay, &protocol.ClientboundKeepAlive{})
+	}
+	return record.ID == id
+}
+
+// describe returns the decoded packet of the record, or its ID and data when
+// it cannot be decoded.
+func describe(record *capture.Record) string {
+	packet, err := record.Packet()
+	if err != nil {
+		return fmt.Sprintf("packet %#02x % x", record.ID, record.Data)
+	}
+	return fmt.Sprintf("%T %+v", packet, packet)
+}
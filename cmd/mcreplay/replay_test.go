@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JDWardle/gocraft/capture"
+	"github.com/JDWardle/gocraft/protocol"
+	"github.com/JDWardle/gocraft/protocol/client"
+	"github.com/JDWardle/gocraft/protocol/server"
+	gocraft "github.com/JDWardle/gocraft/server"
+)
+
+// lockedBuffer is a buffer written by the writer goroutine of a client and
+// read by the test.
+type lockedBuffer struct {
+	b  bytes.Buffer
+	mu sync.Mutex
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.Write(p)
+}
+
+func (b *lockedBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]byte(nil), b.b.Bytes()...)
+}
+
+// startServer serves a server with config on a local port, recording the
+// packets of its clients to the returned buffer.
+func startServer(t *testing.T, config gocraft.Config) (string, *lockedBuffer, <-chan struct{}, func()) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	recorded := &lockedBuffer{}
+	disconnected := make(chan struct{}, 1)
+	s := &gocraft.Server{
+		Config: &config,
+		OnConnect: func(c *gocraft.Client) {
+			w, err := capture.NewWriter(recorded)
+			if err != nil {
+				t.Errorf("Unexpected error: '%v'", err)
+			}
+			c.Record(w)
+		},
+		OnDisconnect: func(c *gocraft.Client) { disconnected <- struct{}{} },
+	}
+	go s.Serve(context.Background(), l)
+
+	return l.Addr().String(), recorded, disconnected, func() { s.Shutdown(context.Background()) }
+}
+
+// login logs Notch in and reads the packets of the login.
+func login(t *testing.T, addr string) {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	defer conn.Close()
+
+	r, w := protocol.NewFrameReader(conn), protocol.NewFrameWriter(conn)
+	write := func(id int32, h protocol.Handler) {
+		data, _ := h.Encode()
+		if err := w.WriteFrame(id, data); err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+	}
+	write(int32(server.Handshake), &protocol.Handshake{ProtocolVersion: 404, ServerAddress: "localhost", ServerPort: 25565, NextState: protocol.ClientStateLogin})
+	write(int32(server.LoginStart), &protocol.LoginStart{Name: "Notch"})
+
+	compression := &protocol.SetCompression{}
+	for _, id := range []client.Login{client.SetCompression, client.LoginSuccess} {
+		frame, err := r.ReadFrame()
+		if err != nil || frame.ID != int32(id) {
+			t.Fatalf("Expected packet %#02x got '%+v' '%v'", id, frame, err)
+		}
+		if id == client.SetCompression {
+			compression.Decode(frame.Reader())
+			r.SetCompression(int(compression.Threshold))
+			w.SetCompression(int(compression.Threshold))
+		}
+	}
+	for _, id := range []client.Play{client.JoinGame, client.SpawnPosition, client.PlayerPositionAndLook} {
+		if frame, err := r.ReadFrame(); err != nil || frame.ID != int32(id) {
+			t.Fatalf("Expected packet %#02x got '%+v' '%v'", id, frame, err)
+		}
+	}
+}
+
+func TestReplay(t *testing.T) {
+	config := gocraft.Config{CompressionThreshold: 16, FlushInterval: time.Millisecond}
+	addr, recorded, disconnected, shutdown := startServer(t, config)
+	defer shutdown()
+
+	login(t, addr)
+	<-disconnected
+	captured := recorded.Bytes()
+
+	var dumped bytes.Buffer
+	captures, err := capture.NewReader(bytes.NewReader(captured))
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	if err := dump(&dumped, captures); err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	for _, expected := range []string{"-> state=2 id=0x00 *protocol.LoginStart &{Name:Notch}", "<- state=2 id=0x03 *protocol.SetCompression &{Threshold:16}", "*protocol.JoinGame"} {
+		if !strings.Contains(dumped.String(), expected) {
+			t.Fatalf("Expected '%s' in '%s'", expected, dumped.String())
+		}
+	}
+
+	tests := map[string]gocraft.Config{
+		"":                                  config,
+		"expected *protocol.SetCompression": {CompressionThreshold: 16, OnlineMode: true, FlushInterval: time.Millisecond},
+	}
+	for expected, config := range tests {
+		addr, _, _, shutdown := startServer(t, config)
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+
+		captures, _ := capture.NewReader(bytes.NewReader(captured))
+		err = newReplayer(conn, time.Second, false).replay(captures)
+		conn.Close()
+		shutdown()
+
+		if expected == "" && err != nil {
+			t.Fatalf("Unexpected error replaying: '%v'", err)
+		}
+		if expected != "" && (err == nil || !strings.Contains(err.Error(), expected)) {
+			t.Fatalf("Expected '%s' got '%v'", expected, err)
+		}
+	}
+}
@@ -1,8 +1,13 @@
 module github.com/JDWardle/gocraft
 
+go 1.21
+
 require (
 	github.com/gofrs/uuid v3.2.0+incompatible
 	github.com/golang/protobuf v1.2.0
+)
+
+require (
 	golang.org/x/sync v0.0.0-20181221193216-37e7f081c4d4 // indirect
 	golang.org/x/tools v0.0.0-20190130015043-a06a922acc1b // indirect
 )
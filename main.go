@@ -1,32 +1,93 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
-	"log"
-	"net"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
 
+	"github.com/JDWardle/gocraft/capture"
 	"github.com/JDWardle/gocraft/server"
 )
 
 func main() {
-	l, err := net.Listen("tcp", ":25565")
-	if err != nil {
-		log.Fatal(err)
+	addr := flag.String("addr", ":25565", "TCP address to listen on")
+	jsonLogs := flag.Bool("log-json", false, "write the logs as JSON")
+	level := flag.String("log-level", "info", "minimum level of the logs: debug, info, warn or error")
+	trace := flag.Bool("trace", false, "log every packet of the clients")
+	captures := flag.String("capture", "", "directory where the packets of each client are recorded, see cmd/mcreplay")
+	flag.Parse()
+
+	var logLevel slog.Level
+	if err := logLevel.UnmarshalText([]byte(*level)); err != nil {
+		slog.Error("invalid log level", "err", err)
+		os.Exit(2)
+	}
+	format := server.LogConsole
+	if *jsonLogs {
+		format = server.LogJSON
+	}
+	logger := server.NewLogger(os.Stderr, format, logLevel)
+
+	config := server.DefaultConfig
+	config.Logger = logger
+	s := &server.Server{Addr: *addr, Config: &config}
+	s.OnConnect = func(c *server.Client) {
+		c.SetTrace(*trace)
+		if *captures != "" {
+			record(c, *captures)
+		}
+	}
+	s.OnDisconnect = func(c *server.Client) {
+		if w := c.Recorder(); w != nil {
+			c.Record(nil)
+			w.Close()
+		}
 	}
-	defer l.Close()
 
-	id := 0
-	for {
-		conn, err := l.Accept()
-		if err != nil {
-			log.Fatal(err)
+	// Closed once the players are disconnected.
+	shutdown := make(chan struct{})
+	go func() {
+		defer close(shutdown)
+
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt)
+		<-interrupt
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := s.Shutdown(ctx); err != nil {
+			logger.Error("shutdown failed", "err", err)
 		}
+	}()
+
+	logger.Info("listening", "addr", *addr)
+	if err := s.ListenAndServe(context.Background()); err != server.ErrServerClosed {
+		logger.Error("server failed", "err", err)
+		os.Exit(1)
+	}
+	<-shutdown
+}
 
-		id++
+// record records the packets of the client to a new file of dir.
+func record(c *server.Client, dir string) {
+	name := filepath.Join(dir, fmt.Sprintf("%s-%d.mccap", time.Now().Format("20060102-150405"), c.ID))
+	f, err := os.Create(name)
+	if err != nil {
+		c.Logger().Error("capture failed", "err", err)
+		return
+	}
 
-		// TODO Keep track of all clients connected to the server.
-		fmt.Printf("new connection from %s\n", conn.RemoteAddr())
-		c := server.NewClient(id, conn)
-		go c.HandleMessages()
+	w, err := capture.NewWriter(f)
+	if err != nil {
+		f.Close()
+		c.Logger().Error("capture failed", "err", err)
+		return
 	}
+	c.Record(w)
+	c.Logger().Info("recording packets", "file", name)
 }
@@ -0,0 +1,84 @@
+package nbt
+
+import (
+	"bufio"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+)
+
+// Compression is the compression used for NBT data stored in files.
+type Compression int
+
+const (
+	None Compression = iota
+	Gzip
+	Zlib
+)
+
+// Decompress returns a reader of the uncompressed NBT data in r. The
+// compression is detected from the first bytes of the data: gzip for
+// level.dat and player data, zlib for region file chunks or none.
+func Decompress(r io.Reader) (io.Reader, Compression, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(2)
+	if err != nil && len(magic) == 0 {
+		return nil, None, err
+	}
+
+	switch {
+	case len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b:
+		zr, err := gzip.NewReader(br)
+		return zr, Gzip, err
+	case len(magic) == 2 && magic[0] == 0x78 && (uint16(magic[0])<<8|uint16(magic[1]))%31 == 0:
+		zr, err := zlib.NewReader(br)
+		return zr, Zlib, err
+	}
+	return br, None, nil
+}
+
+// Compress returns a writer that compresses data written to it before writing
+// it to w. The returned writer must be closed to flush the compressed data.
+func Compress(w io.Writer, c Compression) (io.WriteCloser, error) {
+	switch c {
+	case None:
+		return nopCloser{w}, nil
+	case Gzip:
+		return gzip.NewWriter(w), nil
+	case Zlib:
+		return zlib.NewWriter(w), nil
+	}
+	return nil, fmt.Errorf("nbt: unknown compression %d", c)
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
+
+// ReadCompressed decodes the possibly compressed NBT data from r into the
+// value pointed to by v and returns the name of the root tag.
+func ReadCompressed(r io.Reader, v interface{}) (string, error) {
+	dr, _, err := Decompress(r)
+	if err != nil {
+		return "", err
+	}
+	return NewDecoder(dr).Decode(v)
+}
+
+// WriteCompressed encodes v as a root tag called name and writes it to w using
+// the compression c.
+func WriteCompressed(w io.Writer, c Compression, name string, v interface{}) error {
+	cw, err := Compress(w, c)
+	if err != nil {
+		return err
+	}
+
+	if err := NewEncoder(cw).Encode(name, v); err != nil {
+		cw.Close()
+		return err
+	}
+	return cw.Close()
+}
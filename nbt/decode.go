@@ -0,0 +1,436 @@
+package nbt
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+)
+
+// Unmarshal decodes the NBT encoded data and stores the result in the value
+// pointed to by v. The name of the root tag is ignored. See Marshal for how
+// tags map to Go values; integer tags can be decoded into any integer type
+// large enough to hold them and TAG_Float into float64.
+func Unmarshal(data []byte, v interface{}) error {
+	_, err := NewDecoder(bytes.NewReader(data)).Decode(v)
+	return err
+}
+
+// A Decoder reads NBT data from an input stream.
+type Decoder struct {
+	r   io.Reader
+	buf [8]byte
+}
+
+// NewDecoder returns a new Decoder that reads from r. The Decoder does not
+// read past the end of the root tag if r implements io.ByteReader, otherwise r
+// is buffered.
+func NewDecoder(r io.Reader) *Decoder {
+	if _, ok := r.(io.ByteReader); !ok {
+		r = bufio.NewReader(r)
+	}
+	return &Decoder{r: r}
+}
+
+// Decode reads the next root tag from its input and stores it in the value
+// pointed to by v. Returns the name of the root tag. Returns ErrEnd if the
+// input only contains a TAG_End.
+func (d *Decoder) Decode(v interface{}) (string, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return "", fmt.Errorf("nbt: Decode(non-pointer %T)", v)
+	}
+
+	t, err := d.readByte()
+	if err != nil {
+		return "", err
+	}
+	if TagType(t) == TagEnd {
+		return "", ErrEnd
+	}
+
+	name, err := d.readString()
+	if err != nil {
+		return "", noEOF(err)
+	}
+
+	tag, err := d.readPayload(TagType(t), 0)
+	if err != nil {
+		return name, noEOF(err)
+	}
+
+	return name, assign(rv.Elem(), tag, TagType(t), name)
+}
+
+// readPayload reads the payload of a tag of type t into its generic
+// representation.
+func (d *Decoder) readPayload(t TagType, depth int) (interface{}, error) {
+	switch t {
+	case TagByte:
+		b, err := d.readByte()
+		return int8(b), err
+	case TagShort:
+		if err := d.read(d.buf[:2]); err != nil {
+			return nil, err
+		}
+		return int16(binary.BigEndian.Uint16(d.buf[:2])), nil
+	case TagInt:
+		return d.readInt()
+	case TagLong:
+		return d.readLong()
+	case TagFloat:
+		n, err := d.readInt()
+		return math.Float32frombits(uint32(n)), err
+	case TagDouble:
+		n, err := d.readLong()
+		return math.Float64frombits(uint64(n)), err
+	case TagString:
+		return d.readString()
+	case TagByteArray:
+		n, err := d.readLength(1)
+		if err != nil {
+			return nil, err
+		}
+		return d.readBytes(n)
+	case TagIntArray:
+		n, err := d.readLength(4)
+		if err != nil {
+			return nil, err
+		}
+		a := make([]int32, 0, capLength(n))
+		for i := 0; i < n; i++ {
+			v, err := d.readInt()
+			if err != nil {
+				return nil, err
+			}
+			a = append(a, v)
+		}
+		return a, nil
+	case TagLongArray:
+		n, err := d.readLength(8)
+		if err != nil {
+			return nil, err
+		}
+		a := make([]int64, 0, capLength(n))
+		for i := 0; i < n; i++ {
+			v, err := d.readLong()
+			if err != nil {
+				return nil, err
+			}
+			a = append(a, v)
+		}
+		return a, nil
+	case TagList:
+		if depth >= maxDepth {
+			return nil, ErrTooDeep
+		}
+		elem, err := d.readByte()
+		if err != nil {
+			return nil, err
+		}
+		n, err := d.readLength(1)
+		if err != nil {
+			return nil, err
+		}
+		if TagType(elem) == TagEnd && n > 0 {
+			return nil, fmt.Errorf("nbt: list of %d %s", n, TagEnd)
+		}
+		l := make(List, 0, capLength(n))
+		for i := 0; i < n; i++ {
+			v, err := d.readPayload(TagType(elem), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			l = append(l, v)
+		}
+		return l, nil
+	case TagCompound:
+		if depth >= maxDepth {
+			return nil, ErrTooDeep
+		}
+		c := Compound{}
+		for {
+			t, err := d.readByte()
+			if err != nil {
+				return nil, err
+			}
+			if TagType(t) == TagEnd {
+				return c, nil
+			}
+
+			name, err := d.readString()
+			if err != nil {
+				return nil, err
+			}
+			v, err := d.readPayload(TagType(t), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			c[name] = v
+		}
+	}
+	return nil, fmt.Errorf("nbt: unknown tag type %d", t)
+}
+
+func (d *Decoder) read(b []byte) error {
+	_, err := io.ReadFull(d.r, b)
+	return err
+}
+
+func (d *Decoder) readByte() (byte, error) {
+	return d.r.(io.ByteReader).ReadByte()
+}
+
+func (d *Decoder) readInt() (int32, error) {
+	if err := d.read(d.buf[:4]); err != nil {
+		return 0, err
+	}
+	return int32(binary.BigEndian.Uint32(d.buf[:4])), nil
+}
+
+func (d *Decoder) readLong() (int64, error) {
+	if err := d.read(d.buf[:8]); err != nil {
+		return 0, err
+	}
+	return int64(binary.BigEndian.Uint64(d.buf[:8])), nil
+}
+
+// readLength reads the Int length of an array or list. Lengths are checked
+// against the element size to avoid allocating huge slices for bogus data.
+func (d *Decoder) readLength(size int) (int, error) {
+	n, err := d.readInt()
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 || int64(n)*int64(size) > math.MaxInt32 {
+		return 0, fmt.Errorf("nbt: invalid length %d", n)
+	}
+	return int(n), nil
+}
+
+// capLength limits how much is preallocated for n elements so that a bogus
+// length fails when the data runs out instead of allocating the memory up
+// front.
+func capLength(n int) int {
+	if n > 1024 {
+		return 1024
+	}
+	return n
+}
+
+// readBytes reads exactly n bytes, growing the buffer as data arrives.
+func (d *Decoder) readBytes(n int) ([]byte, error) {
+	if n <= 1<<16 {
+		b := make([]byte, n)
+		return b, d.read(b)
+	}
+
+	var buf bytes.Buffer
+	if _, err := io.CopyN(&buf, d.r, int64(n)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (d *Decoder) readString() (string, error) {
+	if err := d.read(d.buf[:2]); err != nil {
+		return "", err
+	}
+
+	b := make([]byte, binary.BigEndian.Uint16(d.buf[:2]))
+	if err := d.read(b); err != nil {
+		return "", err
+	}
+	return decodeModifiedUTF8(b), nil
+}
+
+// noEOF converts io.EOF into io.ErrUnexpectedEOF since running out of data in
+// the middle of a tag means the data was truncated.
+func noEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// assign stores the generic tag value of type t in v. path is used to report
+// where a mismatch happened.
+func assign(v reflect.Value, tag interface{}, t TagType, path string) error {
+	typeError := func() error {
+		return &TypeError{Tag: t, Type: v.Type().String(), Path: path}
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.NumMethod() != 0 {
+			return typeError()
+		}
+		v.Set(reflect.ValueOf(tag))
+		return nil
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return assign(v.Elem(), tag, t, path)
+	}
+
+	switch tag := tag.(type) {
+	case int8, int16, int32, int64:
+		n := reflect.ValueOf(tag).Int()
+		switch v.Kind() {
+		case reflect.Bool:
+			if t != TagByte {
+				return typeError()
+			}
+			v.SetBool(n != 0)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if v.OverflowInt(n) {
+				return typeError()
+			}
+			v.SetInt(n)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			// Unsigned values are stored using the bits of the signed tag,
+			// SetUint truncates them to the size of v.
+			v.SetUint(uint64(n))
+		default:
+			return typeError()
+		}
+	case float32:
+		if v.Kind() != reflect.Float32 && v.Kind() != reflect.Float64 {
+			return typeError()
+		}
+		v.SetFloat(float64(tag))
+	case float64:
+		if v.Kind() != reflect.Float64 {
+			return typeError()
+		}
+		v.SetFloat(tag)
+	case string:
+		if v.Kind() != reflect.String {
+			return typeError()
+		}
+		v.SetString(tag)
+	case []byte:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			v.SetBytes(tag)
+			return nil
+		}
+		return assignSlice(v, len(tag), func(i int) (interface{}, TagType) { return int8(tag[i]), TagByte }, path)
+	case []int32:
+		return assignSlice(v, len(tag), func(i int) (interface{}, TagType) { return tag[i], TagInt }, path)
+	case []int64:
+		return assignSlice(v, len(tag), func(i int) (interface{}, TagType) { return tag[i], TagLong }, path)
+	case List:
+		return assignSlice(v, len(tag), func(i int) (interface{}, TagType) { return tag[i], tagTypeOf(tag[i]) }, path)
+	case Compound:
+		return assignCompound(v, tag, path)
+	default:
+		return typeError()
+	}
+	return nil
+}
+
+func assignSlice(v reflect.Value, n int, elem func(i int) (interface{}, TagType), path string) error {
+	switch v.Kind() {
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), n, n))
+	case reflect.Array:
+		if v.Len() != n {
+			return fmt.Errorf("nbt: cannot decode %d elements into %s at %s", n, v.Type(), path)
+		}
+	default:
+		t := TagList
+		if n > 0 {
+			_, et := elem(0)
+			t = map[TagType]TagType{TagByte: TagByteArray, TagInt: TagIntArray, TagLong: TagLongArray}[et]
+		}
+		return &TypeError{Tag: t, Type: v.Type().String(), Path: path}
+	}
+
+	for i := 0; i < n; i++ {
+		tag, t := elem(i)
+		if err := assign(v.Index(i), tag, t, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func assignCompound(v reflect.Value, c Compound, path string) error {
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return &TypeError{Tag: TagCompound, Type: v.Type().String(), Path: path}
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		for name, tag := range c {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := assign(elem, tag, tagTypeOf(tag), path+"."+name); err != nil {
+				return err
+			}
+			v.SetMapIndex(reflect.ValueOf(name).Convert(v.Type().Key()), elem)
+		}
+		return nil
+	case reflect.Struct:
+		fields := typeFields(v.Type())
+		for name, tag := range c {
+			f := fieldByName(fields, name)
+			if f == nil {
+				continue
+			}
+
+			fv := v
+			for i, x := range f.index {
+				if i > 0 && fv.Kind() == reflect.Ptr {
+					if fv.IsNil() {
+						fv.Set(reflect.New(fv.Type().Elem()))
+					}
+					fv = fv.Elem()
+				}
+				fv = fv.Field(x)
+			}
+
+			if err := assign(fv, tag, tagTypeOf(tag), path+"."+name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return &TypeError{Tag: TagCompound, Type: v.Type().String(), Path: path}
+}
+
+// tagTypeOf returns the tag type of a generic tag value.
+func tagTypeOf(tag interface{}) TagType {
+	switch tag.(type) {
+	case int8:
+		return TagByte
+	case int16:
+		return TagShort
+	case int32:
+		return TagInt
+	case int64:
+		return TagLong
+	case float32:
+		return TagFloat
+	case float64:
+		return TagDouble
+	case []byte:
+		return TagByteArray
+	case string:
+		return TagString
+	case List:
+		return TagList
+	case Compound:
+		return TagCompound
+	case []int32:
+		return TagIntArray
+	case []int64:
+		return TagLongArray
+	}
+	return TagEnd
+}
@@ -0,0 +1,341 @@
+package nbt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// Marshal returns the NBT encoding of v as a root tag with an empty name.
+//
+// Go values are encoded as follows:
+//
+//	bool, int8, uint8       TAG_Byte
+//	int16, uint16           TAG_Short
+//	int, int32, uint32      TAG_Int
+//	int64, uint64           TAG_Long
+//	float32                 TAG_Float
+//	float64                 TAG_Double
+//	string                  TAG_String
+//	[]byte, []int8          TAG_Byte_Array
+//	[]int32                 TAG_Int_Array
+//	[]int64                 TAG_Long_Array
+//	other slices and arrays TAG_List
+//	structs, map[string]T   TAG_Compound
+//
+// Pointers and interfaces are encoded as the value they point to. See
+// typeFields for the struct tags that control how struct fields are encoded.
+func Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode("", v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// An Encoder writes NBT data to an output stream.
+type Encoder struct {
+	w   io.Writer
+	buf [8]byte
+}
+
+// NewEncoder returns a new Encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode writes v as a root tag called name. A nil v is written as a single
+// TAG_End.
+func (e *Encoder) Encode(name string, v interface{}) error {
+	if v == nil {
+		return e.writeByte(byte(TagEnd))
+	}
+
+	rv := indirect(reflect.ValueOf(v))
+	if !rv.IsValid() {
+		return e.writeByte(byte(TagEnd))
+	}
+
+	t, err := valueTag(rv, false)
+	if err != nil {
+		return err
+	}
+
+	if err := e.writeByte(byte(t)); err != nil {
+		return err
+	}
+	if err := e.writeString(name); err != nil {
+		return err
+	}
+
+	return e.writePayload(rv, t)
+}
+
+// indirect dereferences pointers and interfaces until it reaches a concrete
+// value. Returns the zero Value if a nil pointer or interface is found.
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// valueTag returns the tag type used to encode v, which must not be a pointer
+// or interface. Slices and arrays are forced to TAG_List when list is true.
+func valueTag(v reflect.Value, list bool) (TagType, error) {
+	if list && (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) {
+		return TagList, nil
+	}
+	return typeTag(v.Type())
+}
+
+// typeTag returns the tag type used to encode values of type t.
+func typeTag(t reflect.Type) (TagType, error) {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int8, reflect.Uint8:
+		return TagByte, nil
+	case reflect.Int16, reflect.Uint16:
+		return TagShort, nil
+	case reflect.Int, reflect.Int32, reflect.Uint32:
+		return TagInt, nil
+	case reflect.Int64, reflect.Uint64:
+		return TagLong, nil
+	case reflect.Float32:
+		return TagFloat, nil
+	case reflect.Float64:
+		return TagDouble, nil
+	case reflect.String:
+		return TagString, nil
+	case reflect.Slice, reflect.Array:
+		switch t.Elem().Kind() {
+		case reflect.Int8, reflect.Uint8:
+			return TagByteArray, nil
+		case reflect.Int32:
+			return TagIntArray, nil
+		case reflect.Int64:
+			return TagLongArray, nil
+		}
+		return TagList, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return 0, fmt.Errorf("nbt: unsupported map key type %s", t.Key())
+		}
+		return TagCompound, nil
+	case reflect.Struct:
+		return TagCompound, nil
+	case reflect.Ptr:
+		return typeTag(t.Elem())
+	}
+	return 0, fmt.Errorf("nbt: unsupported type %s", t)
+}
+
+func (e *Encoder) writePayload(v reflect.Value, t TagType) error {
+	switch t {
+	case TagByte:
+		if v.Kind() == reflect.Bool {
+			if v.Bool() {
+				return e.writeByte(1)
+			}
+			return e.writeByte(0)
+		}
+		return e.writeByte(byte(intValue(v)))
+	case TagShort:
+		binary.BigEndian.PutUint16(e.buf[:2], uint16(intValue(v)))
+		return e.write(e.buf[:2])
+	case TagInt:
+		n := intValue(v)
+		if v.Kind() == reflect.Int && (n > math.MaxInt32 || n < math.MinInt32) {
+			return fmt.Errorf("nbt: int value %d overflows TAG_Int", n)
+		}
+		return e.writeInt(int32(n))
+	case TagLong:
+		binary.BigEndian.PutUint64(e.buf[:8], uint64(intValue(v)))
+		return e.write(e.buf[:8])
+	case TagFloat:
+		return e.writeInt(int32(math.Float32bits(float32(v.Float()))))
+	case TagDouble:
+		binary.BigEndian.PutUint64(e.buf[:8], math.Float64bits(v.Float()))
+		return e.write(e.buf[:8])
+	case TagString:
+		return e.writeString(v.String())
+	case TagByteArray, TagIntArray, TagLongArray:
+		if err := e.writeInt(int32(v.Len())); err != nil {
+			return err
+		}
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			return e.write(v.Bytes())
+		}
+		elem := map[TagType]TagType{TagByteArray: TagByte, TagIntArray: TagInt, TagLongArray: TagLong}[t]
+		for i := 0; i < v.Len(); i++ {
+			if err := e.writePayload(v.Index(i), elem); err != nil {
+				return err
+			}
+		}
+		return nil
+	case TagList:
+		return e.writeList(v)
+	case TagCompound:
+		if v.Kind() == reflect.Map {
+			return e.writeMap(v)
+		}
+		return e.writeStruct(v)
+	}
+	return fmt.Errorf("nbt: cannot encode %s as %s", v.Type(), t)
+}
+
+func intValue(v reflect.Value) int64 {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	}
+	return int64(v.Uint())
+}
+
+func (e *Encoder) writeList(v reflect.Value) error {
+	elemType := TagEnd
+	elems := make([]reflect.Value, v.Len())
+	for i := range elems {
+		elems[i] = indirect(v.Index(i))
+		if !elems[i].IsValid() {
+			return fmt.Errorf("nbt: cannot encode nil list element %d", i)
+		}
+
+		t, err := valueTag(elems[i], false)
+		if err != nil {
+			return err
+		}
+
+		if i == 0 {
+			elemType = t
+		} else if t != elemType {
+			return fmt.Errorf("nbt: list element %d is %s, expected %s", i, t, elemType)
+		}
+	}
+
+	// Keep the element type of empty slices when it is known from the Go type.
+	if elemType == TagEnd && v.Type().Elem().Kind() != reflect.Interface {
+		if t, err := typeTag(v.Type().Elem()); err == nil {
+			elemType = t
+		}
+	}
+
+	if err := e.writeByte(byte(elemType)); err != nil {
+		return err
+	}
+	if err := e.writeInt(int32(len(elems))); err != nil {
+		return err
+	}
+
+	for _, elem := range elems {
+		if err := e.writePayload(elem, elemType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Encoder) writeMap(v reflect.Value) error {
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+	for _, k := range keys {
+		elem := indirect(v.MapIndex(k))
+		if !elem.IsValid() {
+			continue
+		}
+		if err := e.writeNamed(k.String(), elem, false); err != nil {
+			return err
+		}
+	}
+	return e.writeByte(byte(TagEnd))
+}
+
+func (e *Encoder) writeStruct(v reflect.Value) error {
+	for _, f := range typeFields(v.Type()) {
+		fv, ok := fieldByIndex(v, f.index)
+		if !ok {
+			continue
+		}
+		if f.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+
+		fv = indirect(fv)
+		if !fv.IsValid() {
+			continue
+		}
+		if err := e.writeNamed(f.name, fv, f.list); err != nil {
+			return err
+		}
+	}
+	return e.writeByte(byte(TagEnd))
+}
+
+// fieldByIndex returns the nested field of v at index, reporting false if it
+// is only reachable through a nil embedded pointer.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+func (e *Encoder) writeNamed(name string, v reflect.Value, list bool) error {
+	t, err := valueTag(v, list)
+	if err != nil {
+		return err
+	}
+
+	if err := e.writeByte(byte(t)); err != nil {
+		return err
+	}
+	if err := e.writeString(name); err != nil {
+		return err
+	}
+
+	if err := e.writePayload(v, t); err != nil {
+		return fmt.Errorf("%s: %v", name, err)
+	}
+	return nil
+}
+
+func (e *Encoder) write(b []byte) error {
+	_, err := e.w.Write(b)
+	return err
+}
+
+func (e *Encoder) writeByte(b byte) error {
+	e.buf[0] = b
+	return e.write(e.buf[:1])
+}
+
+func (e *Encoder) writeInt(n int32) error {
+	binary.BigEndian.PutUint32(e.buf[:4], uint32(n))
+	return e.write(e.buf[:4])
+}
+
+func (e *Encoder) writeString(s string) error {
+	b := encodeModifiedUTF8(s)
+	if len(b) > math.MaxUint16 {
+		return fmt.Errorf("nbt: string of %d bytes is too long", len(b))
+	}
+
+	binary.BigEndian.PutUint16(e.buf[:2], uint16(len(b)))
+	if err := e.write(e.buf[:2]); err != nil {
+		return err
+	}
+	return e.write(b)
+}
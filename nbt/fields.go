@@ -0,0 +1,113 @@
+package nbt
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// field is a struct field that is encoded as an entry of a TAG_Compound.
+type field struct {
+	name      string
+	index     []int
+	omitEmpty bool
+	list      bool
+}
+
+var fieldCache sync.Map // map[reflect.Type][]field
+
+// typeFields returns the fields of the struct type t that are encoded. The
+// name of a field is taken from its "nbt" struct tag and defaults to the Go
+// field name. Embedded structs without a tag have their fields promoted.
+//
+//	Field int32   `nbt:"name"`           // stored under "name"
+//	Field string  `nbt:"name,omitempty"` // not stored when empty
+//	Field []int32 `nbt:"name,list"`      // TAG_List instead of TAG_Int_Array
+//	Field int     `nbt:"-"`              // ignored
+func typeFields(t reflect.Type) []field {
+	if f, ok := fieldCache.Load(t); ok {
+		return f.([]field)
+	}
+
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("nbt")
+		if tag == "-" {
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		if sf.Anonymous && parts[0] == "" {
+			ft := sf.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for _, f := range typeFields(ft) {
+					f.index = append([]int{i}, f.index...)
+					fields = append(fields, f)
+				}
+				continue
+			}
+		}
+
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		f := field{name: parts[0], index: []int{i}}
+		if f.name == "" {
+			f.name = sf.Name
+		}
+		for _, opt := range parts[1:] {
+			switch opt {
+			case "omitempty":
+				f.omitEmpty = true
+			case "list":
+				f.list = true
+			}
+		}
+
+		fields = append(fields, f)
+	}
+
+	fieldCache.Store(t, fields)
+	return fields
+}
+
+// fieldByName returns the field named name, preferring an exact match over a
+// case-insensitive one.
+func fieldByName(fields []field, name string) *field {
+	for i := range fields {
+		if fields[i].name == name {
+			return &fields[i]
+		}
+	}
+	for i := range fields {
+		if strings.EqualFold(fields[i].name, name) {
+			return &fields[i]
+		}
+	}
+	return nil
+}
+
+// isEmptyValue reports whether v is the zero value for the purposes of the
+// omitempty option.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package nbt
+
+import (
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// encodeModifiedUTF8 encodes s using the modified UTF-8 used by Java's
+// DataOutput. NUL is encoded as two bytes and characters outside of the Basic
+// Multilingual Plane are encoded as a surrogate pair of three byte sequences.
+func encodeModifiedUTF8(s string) []byte {
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r == 0:
+			b = append(b, 0xC0, 0x80)
+		case r < 0x80:
+			b = append(b, byte(r))
+		case r < 0x10000:
+			b = appendMUTF8(b, r)
+		default:
+			r1, r2 := utf16.EncodeRune(r)
+			b = appendMUTF8(appendMUTF8(b, r1), r2)
+		}
+	}
+	return b
+}
+
+func appendMUTF8(b []byte, r rune) []byte {
+	if r < 0x800 {
+		return append(b, 0xC0|byte(r>>6), 0x80|byte(r)&0x3F)
+	}
+	return append(b, 0xE0|byte(r>>12), 0x80|byte(r>>6)&0x3F, 0x80|byte(r)&0x3F)
+}
+
+// decodeModifiedUTF8 decodes Java's modified UTF-8. Invalid sequences are
+// replaced with utf8.RuneError.
+func decodeModifiedUTF8(b []byte) string {
+	r := make([]rune, 0, len(b))
+	for i := 0; i < len(b); {
+		c := b[i]
+		switch {
+		case c < 0x80:
+			r = append(r, rune(c))
+			i++
+		case c&0xE0 == 0xC0 && i+1 < len(b):
+			r = append(r, rune(c&0x1F)<<6|rune(b[i+1]&0x3F))
+			i += 2
+		case c&0xF0 == 0xE0 && i+2 < len(b):
+			r = append(r, rune(c&0x0F)<<12|rune(b[i+1]&0x3F)<<6|rune(b[i+2]&0x3F))
+			i += 3
+		default:
+			r = append(r, utf8.RuneError)
+			i++
+		}
+	}
+
+	// Combine surrogate pairs back into a single character.
+	out := make([]rune, 0, len(r))
+	for i := 0; i < len(r); i++ {
+		if utf16.IsSurrogate(r[i]) && i+1 < len(r) {
+			if c := utf16.DecodeRune(r[i], r[i+1]); c != utf8.RuneError {
+				out = append(out, c)
+				i++
+				continue
+			}
+		}
+		out = append(out, r[i])
+	}
+	return string(out)
+}
@@ -0,0 +1,93 @@
+// Package nbt implements encoding and decoding of the Named Binary Tag format
+// used by Minecraft for item data, block entities, chunks and save files.
+//
+// Values are encoded in the uncompressed big-endian form used on the network.
+// Files on disk are usually gzip or zlib compressed, see Decompress and
+// Compress. SNBT, the stringified text form of NBT, is produced by Stringify.
+// See https://wiki.vg/NBT for more info.
+package nbt
+
+import (
+	"errors"
+	"fmt"
+)
+
+// TagType is the ID of an NBT tag.
+type TagType byte
+
+const (
+	TagEnd TagType = iota
+	TagByte
+	TagShort
+	TagInt
+	TagLong
+	TagFloat
+	TagDouble
+	TagByteArray
+	TagString
+	TagList
+	TagCompound
+	TagIntArray
+	TagLongArray
+)
+
+var tagNames = [...]string{
+	TagEnd:       "TAG_End",
+	TagByte:      "TAG_Byte",
+	TagShort:     "TAG_Short",
+	TagInt:       "TAG_Int",
+	TagLong:      "TAG_Long",
+	TagFloat:     "TAG_Float",
+	TagDouble:    "TAG_Double",
+	TagByteArray: "TAG_Byte_Array",
+	TagString:    "TAG_String",
+	TagList:      "TAG_List",
+	TagCompound:  "TAG_Compound",
+	TagIntArray:  "TAG_Int_Array",
+	TagLongArray: "TAG_Long_Array",
+}
+
+func (t TagType) String() string {
+	if int(t) < len(tagNames) {
+		return tagNames[t]
+	}
+	return fmt.Sprintf("TagType(%d)", t)
+}
+
+// Compound is the generic representation of a TAG_Compound. Decoding into an
+// empty interface value produces Compound and List values for compound and
+// list tags, int8, int16, int32, int64, float32, float64 and string for the
+// primitive tags and []byte, []int32 and []int64 for the array tags.
+type Compound map[string]interface{}
+
+// List is the generic representation of a TAG_List. All elements must have the
+// same tag type.
+type List []interface{}
+
+// maxDepth is the maximum nesting of compound and list tags accepted when
+// decoding.
+const maxDepth = 512
+
+var (
+	// ErrTooDeep is returned when decoding data that nests compound and list
+	// tags deeper than the decoder allows.
+	ErrTooDeep = errors.New("nbt: maximum nesting depth exceeded")
+
+	// ErrEnd is returned by Decoder.Decode when the data only contains a
+	// TAG_End, which the protocol uses to represent the absence of NBT data.
+	ErrEnd = errors.New("nbt: no tag present")
+)
+
+// A TypeError describes a tag that could not be stored in a Go value.
+type TypeError struct {
+	Tag  TagType
+	Type string
+	Path string
+}
+
+func (e *TypeError) Error() string {
+	if e.Path != "" {
+		return fmt.Sprintf("nbt: cannot decode %s into Go value of type %s at %s", e.Tag, e.Type, e.Path)
+	}
+	return fmt.Sprintf("nbt: cannot decode %s into Go value of type %s", e.Tag, e.Type)
+}
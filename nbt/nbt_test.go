@@ -0,0 +1,254 @@
+package nbt
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// helloWorld is hello_world.nbt from https://wiki.vg/NBT#Examples
+var helloWorld = []byte{
+	0x0a, 0x00, 0x0b, 0x68, 0x65, 0x6c, 0x6c, 0x6f, 0x20, 0x77, 0x6f, 0x72, 0x6c, 0x64,
+	0x08, 0x00, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x00, 0x09, 0x42, 0x61, 0x6e, 0x61, 0x6e, 0x72, 0x61, 0x6d, 0x61,
+	0x00,
+}
+
+type testEntity struct {
+	ID        string  `nbt:"id"`
+	Health    float32 `nbt:"Health"`
+	OnGround  bool
+	Pos       []float64
+	Motion    []float64 `nbt:",omitempty"`
+	UUIDMost  int64
+	Tags      []string
+	Heightmap []int64
+	Blocks    []byte
+	Sections  []int32 `nbt:"Sections,list"`
+	Inventory []testItem
+	Extra     map[string]int16
+	Skipped   string `nbt:"-"`
+}
+
+type testItem struct {
+	Slot  int8
+	ID    string `nbt:"id"`
+	Count int8
+}
+
+func TestDecodeHelloWorld(t *testing.T) {
+	var v struct {
+		Name string `nbt:"name"`
+	}
+
+	name, err := NewDecoder(bytes.NewReader(helloWorld)).Decode(&v)
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	if name != "hello world" || v.Name != "Bananrama" {
+		t.Fatalf("Expected 'hello world' with name 'Bananrama' got '%s' with name '%s'", name, v.Name)
+	}
+}
+
+func TestEncodeHelloWorld(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode("hello world", Compound{"name": "Bananrama"}); err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	if !bytes.Equal(buf.Bytes(), helloWorld) {
+		t.Fatalf("Expected '%#02x' got '%#02x'", helloWorld, buf.Bytes())
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	e := testEntity{
+		ID:        "minecraft:zombie",
+		Health:    20,
+		OnGround:  true,
+		Pos:       []float64{1.5, 64, -3.25},
+		UUIDMost:  -1,
+		Tags:      []string{},
+		Heightmap: []int64{1, 2, 3},
+		Blocks:    []byte{0, 1, 0xff},
+		Sections:  []int32{4, 5},
+		Inventory: []testItem{{Slot: 0, ID: "minecraft:stone", Count: 64}},
+		Extra:     map[string]int16{"a": 1, "b": -1},
+		Skipped:   "not encoded",
+	}
+
+	b, err := Marshal(e)
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	var decoded testEntity
+	if err := Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	e.Skipped = ""
+	if !reflect.DeepEqual(e, decoded) {
+		t.Fatalf("Expected '%+v' got '%+v'", e, decoded)
+	}
+
+	var generic interface{}
+	if err := Unmarshal(b, &generic); err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	c, ok := generic.(Compound)
+	if !ok {
+		t.Fatalf("Expected a Compound got '%T'", generic)
+	}
+	if _, ok := c["Motion"]; ok {
+		t.Fatalf("Expected empty Motion to be omitted")
+	}
+	if _, ok := c["Sections"].(List); !ok {
+		t.Fatalf("Expected Sections to be a List got '%T'", c["Sections"])
+	}
+	if _, ok := c["Heightmap"].([]int64); !ok {
+		t.Fatalf("Expected Heightmap to be a long array got '%T'", c["Heightmap"])
+	}
+
+	regenerated, err := Marshal(generic)
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	decoded = testEntity{}
+	if err := Unmarshal(regenerated, &decoded); err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	if !reflect.DeepEqual(e, decoded) {
+		t.Fatalf("Expected generic value to decode to '%+v' got '%+v'", e, decoded)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := map[string][]byte{
+		"truncated":       helloWorld[:len(helloWorld)-3],
+		"unknown tag":     []byte{0x0d, 0x00, 0x00},
+		"negative length": []byte{0x07, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff},
+		"list of end":     []byte{0x09, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+		"type mismatch":   []byte{0x0a, 0x00, 0x00, 0x08, 0x00, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x00, 0x00, 0x00},
+	}
+
+	for name, test := range tests {
+		var v struct {
+			Name int32 `nbt:"name"`
+		}
+		if err := Unmarshal(test, &v); err == nil {
+			t.Fatalf("Expected an error for %s", name)
+		}
+	}
+
+	nested := append([]byte{0x09, 0x00, 0x00}, bytes.Repeat([]byte{0x09, 0x00, 0x00, 0x00, 0x01}, maxDepth+1)...)
+	var v interface{}
+	if err := Unmarshal(nested, &v); err != ErrTooDeep {
+		t.Fatalf("Expected '%v' got '%v'", ErrTooDeep, err)
+	}
+
+	if err := Unmarshal([]byte{0x00}, &v); err != ErrEnd {
+		t.Fatalf("Expected '%v' got '%v'", ErrEnd, err)
+	}
+}
+
+func TestCompression(t *testing.T) {
+	for _, c := range []Compression{None, Gzip, Zlib} {
+		var buf bytes.Buffer
+		if err := WriteCompressed(&buf, c, "hello world", Compound{"name": "Bananrama"}); err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+
+		r, detected, err := Decompress(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+		if detected != c {
+			t.Fatalf("Expected compression '%d' to be detected got '%d'", c, detected)
+		}
+
+		var v Compound
+		name, err := NewDecoder(r).Decode(&v)
+		if err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+		if name != "hello world" || v["name"] != "Bananrama" {
+			t.Fatalf("Expected hello world got '%s' '%v'", name, v)
+		}
+	}
+}
+
+func TestStringify(t *testing.T) {
+	tests := map[string]interface{}{
+		`{}`:         Compound{},
+		`[]`:         List{},
+		`1b`:         int8(1),
+		`-2s`:        int16(-2),
+		`3`:          int32(3),
+		`4L`:         int64(4),
+		`1.5f`:       float32(1.5),
+		`2.0d`:       float64(2),
+		`"a \"b\""`:  `a "b"`,
+		`[B;1b,-1b]`: []byte{1, 0xff},
+		`[I;1,2]`:    []int32{1, 2},
+		`[L;1L]`:     []int64{1},
+		`{"a b":1b,id:"minecraft:stone",n:[1s,2s]}`: Compound{"id": "minecraft:stone", "a b": int8(1), "n": List{int16(1), int16(2)}},
+		`{Count:64b,Slot:0b,id:"minecraft:stone"}`:  testItem{ID: "minecraft:stone", Count: 64},
+	}
+
+	for expected, test := range tests {
+		s, err := Stringify(test)
+		if err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+
+		if s != expected {
+			t.Fatalf("Expected '%#v' to be stringified as '%s' got '%s'", test, expected, s)
+		}
+	}
+
+	s, err := StringifyIndent(Compound{"a": List{int32(1)}}, "  ")
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	if expected := "{\n  a: [\n    1\n  ]\n}"; s != expected {
+		t.Fatalf("Expected '%s' got '%s'", expected, s)
+	}
+}
+
+func TestModifiedUTF8(t *testing.T) {
+	tests := map[string][]byte{
+		"abc":  []byte{0x61, 0x62, 0x63},
+		"\x00": []byte{0xc0, 0x80},
+		"é":    []byte{0xc3, 0xa9},
+		"😀":    []byte{0xed, 0xa0, 0xbd, 0xed, 0xb8, 0x80},
+	}
+
+	for test, expected := range tests {
+		b := encodeModifiedUTF8(test)
+		if !bytes.Equal(b, expected) {
+			t.Fatalf("Expected '%s' to be encoded as '%#02x' got '%#02x'", test, expected, b)
+		}
+
+		if s := decodeModifiedUTF8(expected); s != test {
+			t.Fatalf("Expected '%#02x' to decode to '%s' got '%s'", expected, test, s)
+		}
+	}
+}
+
+func BenchmarkMarshal(b *testing.B) {
+	item := testItem{ID: "minecraft:stone", Count: 64}
+	for n := 0; n < b.N; n++ {
+		Marshal(item)
+	}
+}
+
+func BenchmarkUnmarshal(b *testing.B) {
+	data, _ := Marshal(testItem{ID: "minecraft:stone", Count: 64})
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		var item testItem
+		Unmarshal(data, &item)
+	}
+}
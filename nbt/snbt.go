@@ -0,0 +1,201 @@
+package nbt
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Stringify returns the SNBT (stringified NBT) representation of v, the text
+// format used by Minecraft commands. v is first encoded as NBT so any value
+// accepted by Marshal can be stringified.
+func Stringify(v interface{}) (string, error) {
+	return StringifyIndent(v, "")
+}
+
+// StringifyIndent is like Stringify but places every compound entry and list
+// element on its own line indented with indent.
+func StringifyIndent(v interface{}, indent string) (string, error) {
+	tag, err := toGeneric(v)
+	if err != nil {
+		return "", err
+	}
+
+	s := &snbtWriter{indent: indent}
+	s.write(tag, 0)
+	return s.String(), nil
+}
+
+// String returns the SNBT representation of the compound.
+func (c Compound) String() string {
+	s := &snbtWriter{}
+	s.write(c, 0)
+	return s.String()
+}
+
+// String returns the SNBT representation of the list.
+func (l List) String() string {
+	s := &snbtWriter{}
+	s.write(l, 0)
+	return s.String()
+}
+
+// toGeneric converts v into its generic tag representation.
+func toGeneric(v interface{}) (interface{}, error) {
+	switch v.(type) {
+	case nil, int8, int16, int32, int64, float32, float64, string, []byte, []int32, []int64, List, Compound:
+		return v, nil
+	}
+
+	b, err := Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var tag interface{}
+	if err := Unmarshal(b, &tag); err != nil && err != ErrEnd {
+		return nil, err
+	}
+	return tag, nil
+}
+
+var unquotedKey = regexp.MustCompile(`^[0-9A-Za-z_\-.+]+$`)
+
+type snbtWriter struct {
+	strings.Builder
+	indent string
+}
+
+func (s *snbtWriter) newline(depth int) {
+	if s.indent == "" {
+		return
+	}
+	s.WriteByte('\n')
+	s.WriteString(strings.Repeat(s.indent, depth))
+}
+
+func (s *snbtWriter) separator(depth int) {
+	s.WriteByte(',')
+	if s.indent == "" {
+		return
+	}
+	s.newline(depth)
+}
+
+func (s *snbtWriter) write(tag interface{}, depth int) {
+	switch tag := tag.(type) {
+	case int8:
+		s.WriteString(strconv.Itoa(int(tag)) + "b")
+	case int16:
+		s.WriteString(strconv.Itoa(int(tag)) + "s")
+	case int32:
+		s.WriteString(strconv.Itoa(int(tag)))
+	case int64:
+		s.WriteString(strconv.FormatInt(tag, 10) + "L")
+	case float32:
+		s.WriteString(formatFloat(float64(tag), 32) + "f")
+	case float64:
+		s.WriteString(formatFloat(tag, 64) + "d")
+	case string:
+		s.WriteString(quote(tag))
+	case []byte:
+		s.WriteString("[B;")
+		for i, b := range tag {
+			if i > 0 {
+				s.WriteByte(',')
+			}
+			s.WriteString(strconv.Itoa(int(int8(b))) + "b")
+		}
+		s.WriteByte(']')
+	case []int32:
+		s.WriteString("[I;")
+		for i, n := range tag {
+			if i > 0 {
+				s.WriteByte(',')
+			}
+			s.WriteString(strconv.Itoa(int(n)))
+		}
+		s.WriteByte(']')
+	case []int64:
+		s.WriteString("[L;")
+		for i, n := range tag {
+			if i > 0 {
+				s.WriteByte(',')
+			}
+			s.WriteString(strconv.FormatInt(n, 10) + "L")
+		}
+		s.WriteByte(']')
+	case List:
+		s.WriteByte('[')
+		for i, elem := range tag {
+			if i > 0 {
+				s.separator(depth + 1)
+			} else {
+				s.newline(depth + 1)
+			}
+			s.write(elem, depth+1)
+		}
+		if len(tag) > 0 {
+			s.newline(depth)
+		}
+		s.WriteByte(']')
+	case Compound:
+		keys := make([]string, 0, len(tag))
+		for k := range tag {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		s.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				s.separator(depth + 1)
+			} else {
+				s.newline(depth + 1)
+			}
+			if unquotedKey.MatchString(k) {
+				s.WriteString(k)
+			} else {
+				s.WriteString(quote(k))
+			}
+			s.WriteByte(':')
+			if s.indent != "" {
+				s.WriteByte(' ')
+			}
+			s.write(tag[k], depth+1)
+		}
+		if len(keys) > 0 {
+			s.newline(depth)
+		}
+		s.WriteByte('}')
+	}
+}
+
+// formatFloat formats f so it is always read back as a floating point number.
+func formatFloat(f float64, bitSize int) string {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return strconv.FormatFloat(f, 'g', -1, bitSize)
+	}
+
+	str := strconv.FormatFloat(f, 'g', -1, bitSize)
+	if !strings.ContainsAny(str, ".e") {
+		str += ".0"
+	}
+	return str
+}
+
+// quote returns s as a double quoted SNBT string.
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		if r == '"' || r == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,18 @@
+package protocol
+
+import "math"
+
+// Angle is a rotation angle in steps of 1/256 of a full turn.
+// See https://wiki.vg/Protocol#Data_types for more info.
+type Angle uint8
+
+// AngleFromDegrees returns the Angle closest to the rotation d given in
+// degrees. Rotations outside of [0, 360) wrap around.
+func AngleFromDegrees(d float64) Angle {
+	return Angle(int64(math.Round(d*256/360)) & 0xFF)
+}
+
+// Degrees returns the rotation in degrees in the range [0, 360).
+func (a Angle) Degrees() float64 {
+	return float64(a) * 360 / 256
+}
@@ -0,0 +1,60 @@
+package protocol
+
+import "crypto/cipher"
+
+// cfb8 is the 8-bit cipher feedback mode used to encrypt the connection after
+// login. The shared secret is used as both the key and the IV.
+// See https://wiki.vg/Protocol_Encryption for more info.
+type cfb8 struct {
+	block   cipher.Block
+	iv      []byte
+	out     []byte
+	decrypt bool
+}
+
+// NewCFB8Encrypter returns a Stream encrypting with the 8-bit cipher feedback
+// mode, using the given Block. The length of iv must be the same as the
+// Block's block size.
+func NewCFB8Encrypter(block cipher.Block, iv []byte) cipher.Stream {
+	return newCFB8(block, iv, false)
+}
+
+// NewCFB8Decrypter returns a Stream decrypting with the 8-bit cipher feedback
+// mode, using the given Block. The length of iv must be the same as the
+// Block's block size.
+func NewCFB8Decrypter(block cipher.Block, iv []byte) cipher.Stream {
+	return newCFB8(block, iv, true)
+}
+
+func newCFB8(block cipher.Block, iv []byte, decrypt bool) *cfb8 {
+	if len(iv) != block.BlockSize() {
+		panic("protocol: IV length must equal block size")
+	}
+
+	return &cfb8{
+		block:   block,
+		iv:      append([]byte(nil), iv...),
+		out:     make([]byte, block.BlockSize()),
+		decrypt: decrypt,
+	}
+}
+
+func (c *cfb8) XORKeyStream(dst, src []byte) {
+	if len(dst) < len(src) {
+		panic("protocol: output smaller than input")
+	}
+
+	for i, b := range src {
+		c.block.Encrypt(c.out, c.iv)
+
+		// The next IV is shifted by a byte and ends with the ciphertext.
+		copy(c.iv, c.iv[1:])
+		if c.decrypt {
+			c.iv[len(c.iv)-1] = b
+			dst[i] = b ^ c.out[0]
+		} else {
+			dst[i] = b ^ c.out[0]
+			c.iv[len(c.iv)-1] = dst[i]
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package protocol
+
+import (
+	"bytes"
+	"crypto/aes"
+	"testing"
+)
+
+func TestCFB8(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	// Encrypted with openssl enc -aes-128-cfb8.
+	tests := map[string][]byte{
+		"":             {},
+		"a":            {0x13},
+		"Hello Notch!": {0x3a, 0x76, 0x04, 0x6f, 0x63, 0x62, 0xe3, 0xa7, 0xeb, 0x33, 0x87, 0x11},
+	}
+
+	for test, expected := range tests {
+		encrypted := make([]byte, len(test))
+		NewCFB8Encrypter(block, key).XORKeyStream(encrypted, []byte(test))
+		if !bytes.Equal(encrypted, expected) {
+			t.Fatalf("Expected '%s' to be encrypted as '%#02x' got '%#02x'", test, expected, encrypted)
+		}
+
+		// Decrypting a byte at a time must give the same result.
+		decrypted := make([]byte, len(encrypted))
+		d := NewCFB8Decrypter(block, key)
+		for i := range encrypted {
+			d.XORKeyStream(decrypted[i:i+1], encrypted[i:i+1])
+		}
+		if string(decrypted) != test {
+			t.Fatalf("Expected '%#02x' to be decrypted as '%s' got '%s'", encrypted, test, decrypted)
+		}
+	}
+}
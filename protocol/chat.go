@@ -0,0 +1,757 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxChatLength is the maximum number of characters of the JSON encoding of a
+// Chat component. Later versions than 1.13.2 raise it to 262144.
+const MaxChatLength = 32767
+
+// Color is the name of a text color of a Chat component.
+type Color string
+
+const (
+	Black       Color = "black"
+	DarkBlue    Color = "dark_blue"
+	DarkGreen   Color = "dark_green"
+	DarkAqua    Color = "dark_aqua"
+	DarkRed     Color = "dark_red"
+	DarkPurple  Color = "dark_purple"
+	Gold        Color = "gold"
+	Gray        Color = "gray"
+	DarkGray    Color = "dark_gray"
+	Blue        Color = "blue"
+	Green       Color = "green"
+	Aqua        Color = "aqua"
+	Red         Color = "red"
+	LightPurple Color = "light_purple"
+	Yellow      Color = "yellow"
+	White       Color = "white"
+	Reset       Color = "reset"
+)
+
+// legacyColors maps colors to their legacy § formatting code, in the order of
+// the codes.
+var legacyColors = []Color{
+	Black, DarkBlue, DarkGreen, DarkAqua, DarkRed, DarkPurple, Gold, Gray,
+	DarkGray, Blue, Green, Aqua, Red, LightPurple, Yellow, White,
+}
+
+// Valid reports whether c is one of the named colors. The empty Color, which
+// inherits the color of the parent component, is also valid.
+func (c Color) Valid() bool {
+	if c == "" || c == Reset {
+		return true
+	}
+	for _, l := range legacyColors {
+		if c == l {
+			return true
+		}
+	}
+	return false
+}
+
+// ClickAction is the action performed when a Chat component is clicked.
+type ClickAction string
+
+const (
+	OpenURL        ClickAction = "open_url"
+	RunCommand     ClickAction = "run_command"
+	SuggestCommand ClickAction = "suggest_command"
+	ChangePage     ClickAction = "change_page"
+)
+
+// HoverAction is the action performed when a Chat component is hovered over.
+type HoverAction string
+
+const (
+	ShowText   HoverAction = "show_text"
+	ShowItem   HoverAction = "show_item"
+	ShowEntity HoverAction = "show_entity"
+)
+
+// ClickEvent is performed when the player clicks on a Chat component.
+type ClickEvent struct {
+	Action ClickAction `json:"action"`
+	Value  string      `json:"value"`
+}
+
+// HoverEvent is shown when the player hovers over a Chat component. The item
+// and entity of ShowItem and ShowEntity are given as SNBT text in Value.
+type HoverEvent struct {
+	Action HoverAction `json:"action"`
+	Value  Chat        `json:"value"`
+}
+
+// Score is the content of a score Chat component.
+type Score struct {
+	Name      string `json:"name"`
+	Objective string `json:"objective"`
+	Value     string `json:"value,omitempty"`
+}
+
+// Chat is a JSON text component used for chat messages, disconnect reasons,
+// titles, MOTDs and other text shown to players. Exactly one of Text,
+// Translate, Score, Selector and Keybind is the content of the component,
+// Text is used when the others are empty.
+//
+// Style fields that are nil or empty are inherited from the parent component.
+// See https://wiki.vg/Chat for more info.
+type Chat struct {
+	Text      string
+	Translate string
+	With      []Chat
+	Score     *Score
+	Selector  string
+	Keybind   string
+
+	Color         Color
+	Bold          *bool
+	Italic        *bool
+	Underlined    *bool
+	Strikethrough *bool
+	Obfuscated    *bool
+	Insertion     string
+	ClickEvent    *ClickEvent
+	HoverEvent    *HoverEvent
+
+	Extra []Chat
+}
+
+// Text returns a Chat component containing the text s.
+func Text(s string) Chat {
+	return Chat{Text: s}
+}
+
+// Translate returns a Chat component that is translated by the client using
+// the translation key and the arguments with.
+func Translate(key string, with ...Chat) Chat {
+	return Chat{Translate: key, With: with}
+}
+
+// ScoreText returns a Chat component that shows the score of name in the
+// scoreboard objective.
+func ScoreText(name, objective string) Chat {
+	return Chat{Score: &Score{Name: name, Objective: objective}}
+}
+
+// Selector returns a Chat component that shows the names of the entities
+// matched by the target selector s.
+func Selector(s string) Chat {
+	return Chat{Selector: s}
+}
+
+// Keybind returns a Chat component that shows the key bound to the control
+// key, for example "key.jump".
+func Keybind(key string) Chat {
+	return Chat{Keybind: key}
+}
+
+// WithColor returns a copy of c with the color set.
+func (c Chat) WithColor(color Color) Chat {
+	c.Color = color
+	return c
+}
+
+// WithBold returns a copy of c with bold set to b.
+func (c Chat) WithBold(b bool) Chat {
+	c.Bold = &b
+	return c
+}
+
+// WithItalic returns a copy of c with italic set to b.
+func (c Chat) WithItalic(b bool) Chat {
+	c.Italic = &b
+	return c
+}
+
+// WithUnderlined returns a copy of c with underlined set to b.
+func (c Chat) WithUnderlined(b bool) Chat {
+	c.Underlined = &b
+	return c
+}
+
+// WithStrikethrough returns a copy of c with strikethrough set to b.
+func (c Chat) WithStrikethrough(b bool) Chat {
+	c.Strikethrough = &b
+	return c
+}
+
+// WithObfuscated returns a copy of c with obfuscated set to b.
+func (c Chat) WithObfuscated(b bool) Chat {
+	c.Obfuscated = &b
+	return c
+}
+
+// WithInsertion returns a copy of c that inserts s into the chat input when
+// shift clicked.
+func (c Chat) WithInsertion(s string) Chat {
+	c.Insertion = s
+	return c
+}
+
+// OnClick returns a copy of c that performs action with value when clicked.
+func (c Chat) OnClick(action ClickAction, value string) Chat {
+	c.ClickEvent = &ClickEvent{Action: action, Value: value}
+	return c
+}
+
+// OnHover returns a copy of c that performs action with value when hovered
+// over.
+func (c Chat) OnHover(action HoverAction, value Chat) Chat {
+	c.HoverEvent = &HoverEvent{Action: action, Value: value}
+	return c
+}
+
+// Append returns a copy of c with children added to its extra components.
+func (c Chat) Append(children ...Chat) Chat {
+	extra := make([]Chat, 0, len(c.Extra)+len(children))
+	c.Extra = append(append(extra, c.Extra...), children...)
+	return c
+}
+
+// chatJSON is the JSON representation of a Chat component. The order of the
+// fields is the order they are encoded in.
+type chatJSON struct {
+	Text          *string     `json:"text,omitempty"`
+	Translate     string      `json:"translate,omitempty"`
+	With          []Chat      `json:"with,omitempty"`
+	Score         *Score      `json:"score,omitempty"`
+	Selector      string      `json:"selector,omitempty"`
+	Keybind       string      `json:"keybind,omitempty"`
+	Color         Color       `json:"color,omitempty"`
+	Bold          *bool       `json:"bold,omitempty"`
+	Italic        *bool       `json:"italic,omitempty"`
+	Underlined    *bool       `json:"underlined,omitempty"`
+	Strikethrough *bool       `json:"strikethrough,omitempty"`
+	Obfuscated    *bool       `json:"obfuscated,omitempty"`
+	Insertion     string      `json:"insertion,omitempty"`
+	ClickEvent    *ClickEvent `json:"clickEvent,omitempty"`
+	HoverEvent    *HoverEvent `json:"hoverEvent,omitempty"`
+	Extra         []Chat      `json:"extra,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface. Returns an error if the
+// component uses an unknown color or event action.
+func (c Chat) MarshalJSON() ([]byte, error) {
+	if !c.Color.Valid() {
+		return nil, fmt.Errorf("chat: unknown color %q", c.Color)
+	}
+
+	if e := c.ClickEvent; e != nil {
+		switch e.Action {
+		case OpenURL, RunCommand, SuggestCommand, ChangePage:
+		default:
+			return nil, fmt.Errorf("chat: unknown click event action %q", e.Action)
+		}
+	}
+
+	if e := c.HoverEvent; e != nil {
+		switch e.Action {
+		case ShowText, ShowItem, ShowEntity:
+		default:
+			return nil, fmt.Errorf("chat: unknown hover event action %q", e.Action)
+		}
+	}
+
+	j := chatJSON{
+		Translate:     c.Translate,
+		With:          c.With,
+		Score:         c.Score,
+		Selector:      c.Selector,
+		Keybind:       c.Keybind,
+		Color:         c.Color,
+		Bold:          c.Bold,
+		Italic:        c.Italic,
+		Underlined:    c.Underlined,
+		Strikethrough: c.Strikethrough,
+		Obfuscated:    c.Obfuscated,
+		Insertion:     c.Insertion,
+		ClickEvent:    c.ClickEvent,
+		HoverEvent:    c.HoverEvent,
+		Extra:         c.Extra,
+	}
+	if c.Translate == "" && c.Score == nil && c.Selector == "" && c.Keybind == "" {
+		j.Text = &c.Text
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(j); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Decoding is as
+// lenient as the vanilla client: a JSON string or number is a text component,
+// an array is its first element with the remaining elements appended to the
+// extra components and booleans may be given as strings.
+func (c *Chat) UnmarshalJSON(b []byte) error {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return err
+	}
+
+	chat, err := chatFromJSON(v, 0)
+	if err != nil {
+		return err
+	}
+	*c = chat
+	return nil
+}
+
+// maxChatDepth limits the nesting of decoded Chat components.
+const maxChatDepth = 64
+
+func chatFromJSON(v interface{}, depth int) (Chat, error) {
+	if depth > maxChatDepth {
+		return Chat{}, fmt.Errorf("chat: components nested deeper than %d", maxChatDepth)
+	}
+
+	switch v := v.(type) {
+	case nil:
+		return Chat{}, nil
+	case string, json.Number, bool:
+		return Text(primitiveString(v)), nil
+	case []interface{}:
+		if len(v) == 0 {
+			return Chat{}, fmt.Errorf("chat: empty component array")
+		}
+		c, err := chatFromJSON(v[0], depth+1)
+		if err != nil {
+			return Chat{}, err
+		}
+		for _, e := range v[1:] {
+			extra, err := chatFromJSON(e, depth+1)
+			if err != nil {
+				return Chat{}, err
+			}
+			c.Extra = append(c.Extra, extra)
+		}
+		return c, nil
+	case map[string]interface{}:
+		return chatFromObject(v, depth)
+	}
+	return Chat{}, fmt.Errorf("chat: unexpected JSON value %v", v)
+}
+
+func chatFromObject(m map[string]interface{}, depth int) (Chat, error) {
+	var c Chat
+	var err error
+
+	c.Text = primitiveString(m["text"])
+	c.Translate = primitiveString(m["translate"])
+	c.Selector = primitiveString(m["selector"])
+	c.Keybind = primitiveString(m["keybind"])
+	c.Insertion = primitiveString(m["insertion"])
+	c.Color = Color(strings.ToLower(primitiveString(m["color"])))
+
+	if c.With, err = chatList(m["with"], depth); err != nil {
+		return Chat{}, err
+	}
+	if c.Extra, err = chatList(m["extra"], depth); err != nil {
+		return Chat{}, err
+	}
+
+	if s, ok := m["score"].(map[string]interface{}); ok {
+		c.Score = &Score{
+			Name:      primitiveString(s["name"]),
+			Objective: primitiveString(s["objective"]),
+			Value:     primitiveString(s["value"]),
+		}
+	}
+
+	for name, dst := range map[string]**bool{
+		"bold":          &c.Bold,
+		"italic":        &c.Italic,
+		"underlined":    &c.Underlined,
+		"strikethrough": &c.Strikethrough,
+		"obfuscated":    &c.Obfuscated,
+	} {
+		*dst = lenientBool(m[name])
+	}
+
+	if e, ok := m["clickEvent"].(map[string]interface{}); ok {
+		c.ClickEvent = &ClickEvent{
+			Action: ClickAction(primitiveString(e["action"])),
+			Value:  primitiveString(e["value"]),
+		}
+	}
+
+	if e, ok := m["hoverEvent"].(map[string]interface{}); ok {
+		value, err := chatFromJSON(e["value"], depth+1)
+		if err != nil {
+			return Chat{}, err
+		}
+		c.HoverEvent = &HoverEvent{
+			Action: HoverAction(primitiveString(e["action"])),
+			Value:  value,
+		}
+	}
+
+	return c, nil
+}
+
+// chatList decodes a list of components. A single component is accepted in
+// place of a list.
+func chatList(v interface{}, depth int) ([]Chat, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	l, ok := v.([]interface{})
+	if !ok {
+		l = []interface{}{v}
+	}
+
+	chats := make([]Chat, 0, len(l))
+	for _, e := range l {
+		c, err := chatFromJSON(e, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		chats = append(chats, c)
+	}
+	return chats, nil
+}
+
+// primitiveString returns the string representation of a JSON string, number
+// or boolean and the empty string for anything else.
+func primitiveString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return ""
+}
+
+// lenientBool returns the value of a JSON boolean or a string containing a
+// boolean, nil otherwise.
+func lenientBool(v interface{}) *bool {
+	var b bool
+	switch v := v.(type) {
+	case bool:
+		b = v
+	case string:
+		var err error
+		if b, err = strconv.ParseBool(v); err != nil {
+			return nil
+		}
+	default:
+		return nil
+	}
+	return &b
+}
+
+// MarshalProtocol implements the Marshaler interface. A Chat component is
+// encoded as its JSON representation in a String.
+func (c Chat) MarshalProtocol(e *Encoder) error {
+	b, err := c.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	return e.WriteString(string(b))
+}
+
+// UnmarshalProtocol implements the Unmarshaler interface.
+func (c *Chat) UnmarshalProtocol(d *Decoder) error {
+	s, err := d.ReadString(MaxChatLength)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(s), c)
+}
+
+// TranslationFormats holds the format strings used to render translated
+// components as text, keyed by translation key. Translation keys without a
+// format are rendered as the key itself.
+var TranslationFormats = map[string]string{
+	"chat.type.text":                "<%s> %s",
+	"chat.type.emote":               "* %s %s",
+	"chat.type.announcement":        "[%s] %s",
+	"chat.type.admin":               "[%s: %s]",
+	"multiplayer.player.joined":     "%s joined the game",
+	"multiplayer.player.left":       "%s left the game",
+	"multiplayer.disconnect.kicked": "Kicked by an operator",
+}
+
+// style is the resolved formatting of a run of text.
+type style struct {
+	color                                               Color
+	bold, italic, underlined, strikethrough, obfuscated bool
+}
+
+func (s style) inherit(c Chat) style {
+	if c.Color != "" {
+		s.color = c.Color
+		if s.color == Reset {
+			s.color = ""
+		}
+	}
+	for _, f := range []struct {
+		src *bool
+		dst *bool
+	}{
+		{c.Bold, &s.bold},
+		{c.Italic, &s.italic},
+		{c.Underlined, &s.underlined},
+		{c.Strikethrough, &s.strikethrough},
+		{c.Obfuscated, &s.obfuscated},
+	} {
+		if f.src != nil {
+			*f.dst = *f.src
+		}
+	}
+	return s
+}
+
+type textRun struct {
+	text  string
+	style style
+}
+
+// runs flattens the component tree into runs of text with their resolved
+// style.
+func (c Chat) runs(parent style, runs []textRun) []textRun {
+	s := parent.inherit(c)
+
+	switch {
+	case c.Translate != "":
+		format, ok := TranslationFormats[c.Translate]
+		if !ok {
+			format = c.Translate
+		}
+		runs = c.translateRuns(format, s, runs)
+	case c.Score != nil:
+		runs = append(runs, textRun{c.Score.Value, s})
+	case c.Selector != "":
+		runs = append(runs, textRun{c.Selector, s})
+	case c.Keybind != "":
+		runs = append(runs, textRun{c.Keybind, s})
+	default:
+		runs = append(runs, textRun{c.Text, s})
+	}
+
+	for _, e := range c.Extra {
+		runs = e.runs(s, runs)
+	}
+	return runs
+}
+
+// translateRuns renders format, replacing %s and %n$s with the arguments of
+// the translated component.
+func (c Chat) translateRuns(format string, s style, runs []textRun) []textRun {
+	next := 0
+	for {
+		i := strings.IndexByte(format, '%')
+		if i < 0 || i == len(format)-1 {
+			break
+		}
+		runs = append(runs, textRun{format[:i], s})
+		format = format[i+1:]
+
+		if format[0] == '%' {
+			runs = append(runs, textRun{"%", s})
+			format = format[1:]
+			continue
+		}
+
+		arg := -1
+		if j := strings.Index(format, "$s"); j > 0 {
+			if n, err := strconv.Atoi(format[:j]); err == nil {
+				arg = n - 1
+				format = format[j+2:]
+			}
+		}
+		if arg < 0 && format[0] == 's' {
+			arg = next
+			next++
+			format = format[1:]
+		}
+
+		if arg < 0 {
+			runs = append(runs, textRun{"%", s})
+			continue
+		}
+		if arg < len(c.With) {
+			runs = c.With[arg].runs(s, runs)
+		}
+	}
+	return append(runs, textRun{format, s})
+}
+
+// String returns the text of the component and its children without any
+// formatting.
+func (c Chat) String() string {
+	var b strings.Builder
+	for _, r := range c.runs(style{}, nil) {
+		b.WriteString(r.text)
+	}
+	return b.String()
+}
+
+// Legacy returns the text of the component and its children formatted with
+// legacy § codes.
+func (c Chat) Legacy() string {
+	var b strings.Builder
+	var current style
+
+	for _, r := range c.runs(style{}, nil) {
+		if r.text == "" {
+			continue
+		}
+
+		if r.style != current {
+			removed := (current.bold && !r.style.bold) ||
+				(current.italic && !r.style.italic) ||
+				(current.underlined && !r.style.underlined) ||
+				(current.strikethrough && !r.style.strikethrough) ||
+				(current.obfuscated && !r.style.obfuscated)
+
+			added := r.style
+			if removed || r.style.color != current.color {
+				// Color codes reset the formatting so everything is
+				// applied again.
+				if r.style.color == "" {
+					b.WriteString("§r")
+				} else {
+					b.WriteString("§" + string(legacyCode(r.style.color)))
+				}
+			} else {
+				added.bold = r.style.bold && !current.bold
+				added.italic = r.style.italic && !current.italic
+				added.underlined = r.style.underlined && !current.underlined
+				added.strikethrough = r.style.strikethrough && !current.strikethrough
+				added.obfuscated = r.style.obfuscated && !current.obfuscated
+			}
+
+			if added.obfuscated {
+				b.WriteString("§k")
+			}
+			if added.bold {
+				b.WriteString("§l")
+			}
+			if added.strikethrough {
+				b.WriteString("§m")
+			}
+			if added.underlined {
+				b.WriteString("§n")
+			}
+			if added.italic {
+				b.WriteString("§o")
+			}
+			current = r.style
+		}
+
+		b.WriteString(r.text)
+	}
+
+	return b.String()
+}
+
+func legacyCode(c Color) byte {
+	for i, l := range legacyColors {
+		if c == l {
+			return "0123456789abcdef"[i]
+		}
+	}
+	return 'r'
+}
+
+// ParseLegacy converts a string formatted with legacy § codes into a Chat
+// component. Unknown codes are dropped.
+func ParseLegacy(s string) Chat {
+	var parts []Chat
+	var current style
+	var text strings.Builder
+
+	flush := func() {
+		if text.Len() == 0 {
+			return
+		}
+
+		c := Text(text.String())
+		c.Color = current.color
+		for _, f := range []struct {
+			set bool
+			dst **bool
+		}{
+			{current.bold, &c.Bold},
+			{current.italic, &c.Italic},
+			{current.underlined, &c.Underlined},
+			{current.strikethrough, &c.Strikethrough},
+			{current.obfuscated, &c.Obfuscated},
+		} {
+			if f.set {
+				t := true
+				*f.dst = &t
+			}
+		}
+
+		parts = append(parts, c)
+		text.Reset()
+	}
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '§' {
+			text.WriteRune(runes[i])
+			continue
+		}
+
+		if i+1 >= len(runes) {
+			break
+		}
+		i++
+
+		code := strings.ToLower(string(runes[i]))
+		next := current
+		switch {
+		case strings.Contains("0123456789abcdef", code):
+			n := strings.Index("0123456789abcdef", code)
+			next = style{color: legacyColors[n]}
+		case code == "k":
+			next.obfuscated = true
+		case code == "l":
+			next.bold = true
+		case code == "m":
+			next.strikethrough = true
+		case code == "n":
+			next.underlined = true
+		case code == "o":
+			next.italic = true
+		case code == "r":
+			next = style{}
+		default:
+			continue
+		}
+
+		if next != current {
+			flush()
+			current = next
+		}
+	}
+	flush()
+
+	switch len(parts) {
+	case 0:
+		return Text("")
+	case 1:
+		return parts[0]
+	}
+	return Text("").Append(parts...)
+}
@@ -0,0 +1,177 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestChatMarshalJSON(t *testing.T) {
+	tests := map[string]Chat{
+		`{"text":""}`:                              Chat{},
+		`{"text":"Hello <world>"}`:                 Text("Hello <world>"),
+		`{"text":"Hi","color":"gold","bold":true}`: Text("Hi").WithColor(Gold).WithBold(true),
+		`{"translate":"chat.type.text","with":[{"text":"Notch"},{"text":"hi"}]}`: Translate("chat.type.text", Text("Notch"), Text("hi")),
+		`{"score":{"name":"@p","objective":"kills"}}`:                            ScoreText("@p", "kills"),
+		`{"selector":"@a"}`:                                  Selector("@a"),
+		`{"keybind":"key.jump"}`:                             Keybind("key.jump"),
+		`{"text":"a","extra":[{"text":"b","italic":false}]}`: Text("a").Append(Text("b").WithItalic(false)),
+		`{"text":"x","insertion":"y","clickEvent":{"action":"open_url","value":"https://wiki.vg"},"hoverEvent":{"action":"show_text","value":{"text":"z"}}}`: Text("x").WithInsertion("y").OnClick(OpenURL, "https://wiki.vg").OnHover(ShowText, Text("z")),
+	}
+
+	for expected, test := range tests {
+		b, err := test.MarshalJSON()
+		if err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+
+		if string(b) != expected {
+			t.Fatalf("Expected '%+v' to be encoded as '%s' got '%s'", test, expected, b)
+		}
+
+		var c Chat
+		if err := json.Unmarshal(b, &c); err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+
+		if !reflect.DeepEqual(c, test) {
+			t.Fatalf("Expected '%s' to decode to '%+v' got '%+v'", b, test, c)
+		}
+	}
+
+	errorTests := []Chat{
+		Text("a").WithColor("pink"),
+		Text("a").OnClick("open_file", "/etc/passwd"),
+		Text("a").OnHover("show_achievement", Text("b")),
+		Text("a").Append(Text("b").WithColor("pink")),
+	}
+
+	for _, test := range errorTests {
+		if b, err := json.Marshal(test); err == nil {
+			t.Fatalf("Expected an error encoding '%+v' got '%s'", test, b)
+		}
+	}
+}
+
+func TestChatUnmarshalJSONLenient(t *testing.T) {
+	tests := map[string]Chat{
+		`"plain"`:                              Text("plain"),
+		`42`:                                   Text("42"),
+		`true`:                                 Text("true"),
+		`null`:                                 Chat{},
+		`["a", {"text": "b"}, "c"]`:            Text("a").Append(Text("b"), Text("c")),
+		`{"text": 1.5, "bold": "true"}`:        Text("1.5").WithBold(true),
+		`{"text": "a", "color": "GOLD"}`:       Text("a").WithColor(Gold),
+		`{"text": "a", "extra": "b"}`:          Text("a").Append(Text("b")),
+		`{"translate": "x", "with": ["a", 1]}`: Translate("x", Text("a"), Text("1")),
+		`{"text": "a", "hoverEvent": {"action": "show_text", "value": ["b", "c"]}}`: Text("a").OnHover(ShowText, Text("b").Append(Text("c"))),
+		`{"text": "a", "unknown": {"nested": true}}`:                                Text("a"),
+	}
+
+	for test, expected := range tests {
+		var c Chat
+		if err := json.Unmarshal([]byte(test), &c); err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+
+		if !reflect.DeepEqual(c, expected) {
+			t.Fatalf("Expected '%s' to decode to '%+v' got '%+v'", test, expected, c)
+		}
+	}
+
+	errorTests := []string{`[]`, `{"text": "a"`, `[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[[["deep"]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]]`}
+
+	for _, test := range errorTests {
+		var c Chat
+		if err := json.Unmarshal([]byte(test), &c); err == nil {
+			t.Fatalf("Expected an error decoding '%s'", test)
+		}
+	}
+}
+
+func TestChatLegacy(t *testing.T) {
+	tests := map[string]Chat{
+		"plain":            Text("plain"),
+		"§6gold":           Text("gold").WithColor(Gold),
+		"§6§lbold gold§r.": Text("").Append(Text("bold gold").WithColor(Gold).WithBold(true), Text(".")),
+		"§6a§lb§6c":        Text("").Append(Text("a").WithColor(Gold), Text("b").WithColor(Gold).WithBold(true), Text("c").WithColor(Gold)),
+		"§k§l§m§n§oall":    Text("all").WithObfuscated(true).WithBold(true).WithStrikethrough(true).WithUnderlined(true).WithItalic(true),
+		"§cred§agreen":     Text("").Append(Text("red").WithColor(Red), Text("green").WithColor(Green)),
+	}
+
+	for expected, test := range tests {
+		if s := test.Legacy(); s != expected {
+			t.Fatalf("Expected '%+v' to be formatted as '%s' got '%s'", test, expected, s)
+		}
+
+		if c := ParseLegacy(expected); !reflect.DeepEqual(c, test) {
+			t.Fatalf("Expected '%s' to parse to '%+v' got '%+v'", expected, test, c)
+		}
+	}
+
+	inherited := Text("a").WithColor(Red).WithBold(true).Append(Text("b"), Text("c").WithBold(false), Text("d").WithColor(Reset))
+	if s, expected := inherited.Legacy(), "§c§lab§cc§r§ld"; s != expected {
+		t.Fatalf("Expected '%s' got '%s'", expected, s)
+	}
+
+	if c, expected := ParseLegacy("§zunknown§"), Text("unknown"); !reflect.DeepEqual(c, expected) {
+		t.Fatalf("Expected '%+v' got '%+v'", expected, c)
+	}
+}
+
+func TestChatString(t *testing.T) {
+	tests := map[string]Chat{
+		"Hello world":           Text("Hello ").WithColor(Red).Append(Text("world")),
+		"<Notch> hi":            Translate("chat.type.text", Text("Notch"), Text("hi")),
+		"b a 100%":              Translate("%2$s %1$s 100%%", Text("a"), Text("b")),
+		"unknown.key":           Translate("unknown.key", Text("a")),
+		"Notch joined the game": Translate("multiplayer.player.joined", Text("Notch").WithColor(Yellow)),
+	}
+
+	for expected, test := range tests {
+		if s := test.String(); s != expected {
+			t.Fatalf("Expected '%+v' to render as '%s' got '%s'", test, expected, s)
+		}
+	}
+}
+
+func TestChatMarshalProtocol(t *testing.T) {
+	c := Text("Hello").WithColor(Gold)
+
+	b, err := Marshal(c)
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	expected := String(`{"text":"Hello","color":"gold"}`)
+	if !bytes.Equal(b, expected) {
+		t.Fatalf("Expected '%+v' to be encoded as '%#02x' got '%#02x'", c, expected, b)
+	}
+
+	var decoded Chat
+	if err := Unmarshal(bytes.NewReader(b), &decoded); err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	if !reflect.DeepEqual(c, decoded) {
+		t.Fatalf("Expected '%#02x' to decode to '%+v' got '%+v'", b, c, decoded)
+	}
+}
+
+func TestChatMaxLength(t *testing.T) {
+	tests := map[int]bool{
+		MaxChatLength:     true,
+		MaxChatLength + 1: false,
+	}
+
+	for length, valid := range tests {
+		// {"text":"aaa…"} of length characters.
+		b := String(`{"text":"` + strings.Repeat("a", length-11) + `"}`)
+		var c Chat
+		if err := Unmarshal(bytes.NewReader(b), &c); (err == nil) != valid {
+			t.Fatalf("Expected a Chat of %d characters to be valid: %t got '%v'", length, valid, err)
+		}
+	}
+}
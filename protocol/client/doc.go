@@ -0,0 +1,4 @@
+// Package client contains the IDs of the clientbound packets of protocol 404
+// (1.13.2).
+// See https://wiki.vg/index.php?title=Protocol&oldid=14889 for more info.
+package client
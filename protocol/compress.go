@@ -0,0 +1,46 @@
+package protocol
+
+import (
+	"compress/zlib"
+	"io"
+	"sync"
+)
+
+// The zlib readers and writers are pooled as allocating them for every
+// compressed packet is expensive.
+var (
+	zlibReaders sync.Pool // io.ReadCloser implementing zlib.Resetter
+	zlibWriters = sync.Pool{
+		New: func() interface{} { return zlib.NewWriter(nil) },
+	}
+)
+
+// getZlibReader returns a pooled reader decompressing r.
+func getZlibReader(r io.Reader) (io.ReadCloser, error) {
+	zr, ok := zlibReaders.Get().(io.ReadCloser)
+	if !ok {
+		return zlib.NewReader(r)
+	}
+
+	if err := zr.(zlib.Resetter).Reset(r, nil); err != nil {
+		zlibReaders.Put(zr)
+		return nil, err
+	}
+	return zr, nil
+}
+
+func putZlibReader(zr io.ReadCloser) {
+	zlibReaders.Put(zr)
+}
+
+// getZlibWriter returns a pooled writer compressing to w. It must be closed
+// before being put back.
+func getZlibWriter(w io.Writer) *zlib.Writer {
+	zw := zlibWriters.Get().(*zlib.Writer)
+	zw.Reset(w)
+	return zw
+}
+
+func putZlibWriter(zw *zlib.Writer) {
+	zlibWriters.Put(zw)
+}
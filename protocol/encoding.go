@@ -0,0 +1,788 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// MaxStringLength is the maximum number of characters a String can hold when
+// no smaller limit is given.
+// See https://wiki.vg/Protocol#Data_types for more info.
+const MaxStringLength = 32767
+
+// Marshaler is the interface implemented by types that can encode themselves
+// into the protocol wire format.
+type Marshaler interface {
+	MarshalProtocol(e *Encoder) error
+}
+
+// Unmarshaler is the interface implemented by types that can decode
+// themselves from the protocol wire format.
+type Unmarshaler interface {
+	UnmarshalProtocol(d *Decoder) error
+}
+
+// Marshal returns the protocol encoding of v.
+//
+// Struct fields are encoded in the order they are declared. The wire type of a
+// field is inferred from its Go type and can be changed with the "mc" struct
+// tag. The first item of the tag is the wire type, the rest are options:
+//
+//	Field int32  `mc:"varint"`          // VarInt instead of Int
+//	Field int64  `mc:"varlong"`         // VarLong instead of Long
+//	Field string `mc:"string,max=255"`  // String(255)
+//	Field Position `mc:"position"`      // Position
+//	Field *int32 `mc:"varint,optional"` // Boolean followed by the value if true
+//	Field []byte `mc:",rest"`           // the remaining bytes of the packet
+//	Field []Item `mc:",len=byte"`       // array prefixed by a Byte count
+//	Field int    `mc:"-"`               // field is ignored
+//
+// Without a tag bool, int8, uint8, int16, uint16, int32, uint32, int64,
+// uint64, float32 and float64 are encoded as their fixed width big-endian
+// equivalents, strings as String(32767), slices as arrays prefixed with a
+// VarInt count, fixed size arrays as their elements and structs as their
+// fields. Types implementing Marshaler encode themselves.
+func Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes the protocol encoded data from r and stores the result in
+// the value pointed to by v. See Marshal for the supported types and tags.
+func Unmarshal(r io.Reader, v interface{}) error {
+	return NewDecoder(r).Decode(v)
+}
+
+// An Encoder writes protocol encoded values to an output stream.
+type Encoder struct {
+	w   io.Writer
+	buf [8]byte
+}
+
+// NewEncoder returns a new Encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode writes the protocol encoding of v to the stream.
+func (e *Encoder) Encode(v interface{}) error {
+	if m, ok := v.(Marshaler); ok {
+		return m.MarshalProtocol(e)
+	}
+
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return errors.New("protocol: Encode(nil)")
+	}
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return fmt.Errorf("protocol: Encode(nil %s)", rv.Type())
+		}
+		rv = rv.Elem()
+	}
+
+	return e.encode(rv, fieldOptions{})
+}
+
+// Write writes raw bytes to the stream.
+func (e *Encoder) Write(p []byte) (int, error) {
+	return e.w.Write(p)
+}
+
+// WriteByte writes a single raw byte to the stream.
+func (e *Encoder) WriteByte(b byte) error {
+	e.buf[0] = b
+	_, err := e.w.Write(e.buf[:1])
+	return err
+}
+
+// WriteBool writes a Boolean.
+func (e *Encoder) WriteBool(v bool) error {
+	if v {
+		return e.WriteByte(0x01)
+	}
+	return e.WriteByte(0x00)
+}
+
+// WriteUint16 writes an Unsigned Short.
+func (e *Encoder) WriteUint16(v uint16) error {
+	binary.BigEndian.PutUint16(e.buf[:2], v)
+	_, err := e.w.Write(e.buf[:2])
+	return err
+}
+
+// WriteUint32 writes the big-endian representation of v.
+func (e *Encoder) WriteUint32(v uint32) error {
+	binary.BigEndian.PutUint32(e.buf[:4], v)
+	_, err := e.w.Write(e.buf[:4])
+	return err
+}
+
+// WriteUint64 writes the big-endian representation of v.
+func (e *Encoder) WriteUint64(v uint64) error {
+	binary.BigEndian.PutUint64(e.buf[:8], v)
+	_, err := e.w.Write(e.buf[:8])
+	return err
+}
+
+// WriteFloat32 writes a Float.
+func (e *Encoder) WriteFloat32(v float32) error {
+	return e.WriteUint32(math.Float32bits(v))
+}
+
+// WriteFloat64 writes a Double.
+func (e *Encoder) WriteFloat64(v float64) error {
+	return e.WriteUint64(math.Float64bits(v))
+}
+
+// WriteVarInt writes a VarInt.
+func (e *Encoder) WriteVarInt(v int32) error {
+	_, err := e.w.Write(VarInt(v))
+	return err
+}
+
+// WriteVarLong writes a VarLong.
+func (e *Encoder) WriteVarLong(v int64) error {
+	_, err := e.w.Write(VarLong(v))
+	return err
+}
+
+// WriteString writes a String.
+func (e *Encoder) WriteString(s string) error {
+	_, err := e.w.Write(String(s))
+	return err
+}
+
+// A Decoder reads protocol encoded values from an input stream.
+type Decoder struct {
+	r  io.Reader
+	br io.ByteReader
+}
+
+// NewDecoder returns a new Decoder that reads from r. If r does not implement
+// io.ByteReader the Decoder reads from it one byte at a time when decoding
+// VarInts so that no data past the decoded value is consumed.
+func NewDecoder(r io.Reader) *Decoder {
+	d := &Decoder{r: r}
+	if br, ok := r.(io.ByteReader); ok {
+		d.br = br
+	} else {
+		d.br = &byteReader{r: r}
+	}
+	return d
+}
+
+// Decode reads the next protocol encoded value from its input and stores it
+// in the value pointed to by v.
+func (d *Decoder) Decode(v interface{}) error {
+	if u, ok := v.(Unmarshaler); ok {
+		return u.UnmarshalProtocol(d)
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("protocol: Decode(non-pointer %T)", v)
+	}
+
+	return d.decode(rv.Elem(), fieldOptions{})
+}
+
+// Read reads raw bytes from the stream.
+func (d *Decoder) Read(p []byte) (int, error) {
+	return d.r.Read(p)
+}
+
+// ReadByte reads a single raw byte from the stream.
+func (d *Decoder) ReadByte() (byte, error) {
+	return d.br.ReadByte()
+}
+
+// ReadFull reads exactly len(p) bytes from the stream.
+func (d *Decoder) ReadFull(p []byte) error {
+	_, err := io.ReadFull(d.r, p)
+	return noEOF(err)
+}
+
+// ReadBool reads a Boolean.
+func (d *Decoder) ReadBool() (bool, error) {
+	b, err := d.ReadByte()
+	if err != nil {
+		return false, err
+	}
+
+	switch b {
+	case 0x00:
+		return false, nil
+	case 0x01:
+		return true, nil
+	default:
+		return false, fmt.Errorf("protocol: invalid Boolean %#02x", b)
+	}
+}
+
+// ReadUint16 reads an Unsigned Short.
+func (d *Decoder) ReadUint16() (uint16, error) {
+	var b [2]byte
+	if err := d.ReadFull(b[:]); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint16(b[:]), nil
+}
+
+// ReadUint32 reads a big-endian uint32.
+func (d *Decoder) ReadUint32() (uint32, error) {
+	var b [4]byte
+	if err := d.ReadFull(b[:]); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint32(b[:]), nil
+}
+
+// ReadUint64 reads a big-endian uint64.
+func (d *Decoder) ReadUint64() (uint64, error) {
+	var b [8]byte
+	if err := d.ReadFull(b[:]); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(b[:]), nil
+}
+
+// ReadFloat32 reads a Float.
+func (d *Decoder) ReadFloat32() (float32, error) {
+	v, err := d.ReadUint32()
+	return math.Float32frombits(v), err
+}
+
+// ReadFloat64 reads a Double.
+func (d *Decoder) ReadFloat64() (float64, error) {
+	v, err := d.ReadUint64()
+	return math.Float64frombits(v), err
+}
+
+// ReadVarInt reads a VarInt.
+func (d *Decoder) ReadVarInt() (int32, error) {
+	v, err := ReadVarInt(d.br)
+	return v, noEOF(err)
+}
+
+// ReadVarLong reads a VarLong.
+func (d *Decoder) ReadVarLong() (int64, error) {
+	v, err := ReadVarLong(d.br)
+	return v, noEOF(err)
+}
+
+// ReadString reads a String of at most max characters.
+func (d *Decoder) ReadString(max int) (string, error) {
+	s, err := ReadStringMax(d.br, max)
+	return s, noEOF(err)
+}
+
+// byteReader adapts an io.Reader to an io.ByteReader without reading ahead.
+type byteReader struct {
+	r   io.Reader
+	buf [1]byte
+}
+
+func (b *byteReader) ReadByte() (byte, error) {
+	if _, err := io.ReadFull(b.r, b.buf[:]); err != nil {
+		return 0, err
+	}
+	return b.buf[0], nil
+}
+
+// noEOF converts io.EOF into io.ErrUnexpectedEOF since running out of data in
+// the middle of a value means the data was truncated.
+func noEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// fieldOptions holds the parsed "mc" struct tag of a field.
+type fieldOptions struct {
+	kind     string
+	max      int
+	length   string
+	optional bool
+	rest     bool
+}
+
+func parseTag(tag string) (fieldOptions, error) {
+	parts := strings.Split(tag, ",")
+	opts := fieldOptions{kind: parts[0]}
+
+	for _, p := range parts[1:] {
+		switch {
+		case p == "optional":
+			opts.optional = true
+		case p == "rest":
+			opts.rest = true
+		case strings.HasPrefix(p, "max="):
+			n, err := strconv.Atoi(strings.TrimPrefix(p, "max="))
+			if err != nil || n < 0 {
+				return opts, fmt.Errorf("invalid max %q", p)
+			}
+			opts.max = n
+		case strings.HasPrefix(p, "len="):
+			opts.length = strings.TrimPrefix(p, "len=")
+			switch opts.length {
+			case "varint", "byte", "short", "int":
+			default:
+				return opts, fmt.Errorf("invalid len %q", p)
+			}
+		default:
+			return opts, fmt.Errorf("unknown option %q", p)
+		}
+	}
+
+	switch opts.kind {
+	case "", "varint", "varlong", "string", "position":
+	default:
+		return opts, fmt.Errorf("unknown type %q", opts.kind)
+	}
+
+	return opts, nil
+}
+
+type field struct {
+	name  string
+	index int
+	opts  fieldOptions
+}
+
+var fieldCache sync.Map // map[reflect.Type][]field
+
+// typeFields returns the encodable fields of the struct type t.
+func typeFields(t reflect.Type) ([]field, error) {
+	if f, ok := fieldCache.Load(t); ok {
+		return f.([]field), nil
+	}
+
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		tag := sf.Tag.Get("mc")
+		if tag == "-" {
+			continue
+		}
+
+		opts, err := parseTag(tag)
+		if err != nil {
+			return nil, fmt.Errorf("protocol: %s.%s: %v", t, sf.Name, err)
+		}
+
+		fields = append(fields, field{name: sf.Name, index: i, opts: opts})
+	}
+
+	fieldCache.Store(t, fields)
+	return fields, nil
+}
+
+var (
+	marshalerType   = reflect.TypeOf((*Marshaler)(nil)).Elem()
+	unmarshalerType = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+)
+
+func (e *Encoder) encode(v reflect.Value, opts fieldOptions) error {
+	if opts.optional {
+		if v.Kind() != reflect.Ptr {
+			return fmt.Errorf("protocol: optional requires a pointer, got %s", v.Type())
+		}
+		if err := e.WriteBool(!v.IsNil()); err != nil || v.IsNil() {
+			return err
+		}
+		opts.optional = false
+	}
+
+	if v.Type().Implements(marshalerType) {
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			return fmt.Errorf("protocol: cannot encode nil %s", v.Type())
+		}
+		return v.Interface().(Marshaler).MarshalProtocol(e)
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(marshalerType) {
+		return v.Addr().Interface().(Marshaler).MarshalProtocol(e)
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return fmt.Errorf("protocol: cannot encode nil %s", v.Type())
+		}
+		return e.encode(v.Elem(), opts)
+	}
+
+	switch opts.kind {
+	case "varint":
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return e.WriteVarInt(int32(v.Int()))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return e.WriteVarInt(int32(v.Uint()))
+		case reflect.Slice, reflect.Array:
+		default:
+			return fmt.Errorf("protocol: varint requires an integer, got %s", v.Type())
+		}
+	case "varlong":
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return e.WriteVarLong(v.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return e.WriteVarLong(int64(v.Uint()))
+		case reflect.Slice, reflect.Array:
+		default:
+			return fmt.Errorf("protocol: varlong requires an integer, got %s", v.Type())
+		}
+	case "position":
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return fmt.Errorf("protocol: position requires a Position, got %s", v.Type())
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return e.WriteBool(v.Bool())
+	case reflect.Int8:
+		return e.WriteByte(byte(v.Int()))
+	case reflect.Uint8:
+		return e.WriteByte(byte(v.Uint()))
+	case reflect.Int16:
+		return e.WriteUint16(uint16(v.Int()))
+	case reflect.Uint16:
+		return e.WriteUint16(uint16(v.Uint()))
+	case reflect.Int32:
+		return e.WriteUint32(uint32(v.Int()))
+	case reflect.Uint32:
+		return e.WriteUint32(uint32(v.Uint()))
+	case reflect.Int64:
+		return e.WriteUint64(uint64(v.Int()))
+	case reflect.Uint64:
+		return e.WriteUint64(v.Uint())
+	case reflect.Float32:
+		return e.WriteFloat32(float32(v.Float()))
+	case reflect.Float64:
+		return e.WriteFloat64(v.Float())
+	case reflect.String:
+		max := opts.max
+		if max == 0 {
+			max = MaxStringLength
+		}
+		if n := utf8.RuneCountInString(v.String()); n > max {
+			return fmt.Errorf("protocol: String length %d exceeds maximum of %d", n, max)
+		}
+		return e.WriteString(v.String())
+	case reflect.Slice:
+		if !opts.rest {
+			if err := e.writeLength(v.Len(), opts); err != nil {
+				return err
+			}
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 && opts.kind == "" {
+			_, err := e.Write(v.Bytes())
+			return err
+		}
+		return e.encodeElements(v, opts)
+	case reflect.Array:
+		return e.encodeElements(v, opts)
+	case reflect.Struct:
+		fields, err := typeFields(v.Type())
+		if err != nil {
+			return err
+		}
+		for _, f := range fields {
+			if err := e.encode(v.Field(f.index), f.opts); err != nil {
+				return fmt.Errorf("%s.%s: %v", v.Type(), f.name, err)
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("protocol: unsupported type %s", v.Type())
+}
+
+func (e *Encoder) encodeElements(v reflect.Value, opts fieldOptions) error {
+	elemOpts := fieldOptions{kind: opts.kind, max: opts.max}
+	for i := 0; i < v.Len(); i++ {
+		if err := e.encode(v.Index(i), elemOpts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Encoder) writeLength(n int, opts fieldOptions) error {
+	if opts.max > 0 && n > opts.max && opts.kind != "string" {
+		return fmt.Errorf("protocol: array length %d exceeds maximum of %d", n, opts.max)
+	}
+
+	switch opts.length {
+	case "byte":
+		return e.WriteByte(byte(n))
+	case "short":
+		return e.WriteUint16(uint16(n))
+	case "int":
+		return e.WriteUint32(uint32(n))
+	default:
+		return e.WriteVarInt(int32(n))
+	}
+}
+
+func (d *Decoder) decode(v reflect.Value, opts fieldOptions) error {
+	if opts.optional {
+		if v.Kind() != reflect.Ptr {
+			return fmt.Errorf("protocol: optional requires a pointer, got %s", v.Type())
+		}
+		present, err := d.ReadBool()
+		if err != nil {
+			return err
+		}
+		if !present {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		opts.optional = false
+	}
+
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		if v.Type().Implements(unmarshalerType) {
+			return v.Interface().(Unmarshaler).UnmarshalProtocol(d)
+		}
+		return d.decode(v.Elem(), opts)
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(unmarshalerType) {
+		return v.Addr().Interface().(Unmarshaler).UnmarshalProtocol(d)
+	}
+
+	switch opts.kind {
+	case "varint":
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n, err := d.ReadVarInt()
+			v.SetInt(int64(n))
+			return err
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			n, err := d.ReadVarInt()
+			v.SetUint(uint64(uint32(n)))
+			return err
+		case reflect.Slice, reflect.Array:
+		default:
+			return fmt.Errorf("protocol: varint requires an integer, got %s", v.Type())
+		}
+	case "varlong":
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n, err := d.ReadVarLong()
+			v.SetInt(n)
+			return err
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			n, err := d.ReadVarLong()
+			v.SetUint(uint64(n))
+			return err
+		case reflect.Slice, reflect.Array:
+		default:
+			return fmt.Errorf("protocol: varlong requires an integer, got %s", v.Type())
+		}
+	case "position":
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return fmt.Errorf("protocol: position requires a Position, got %s", v.Type())
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		b, err := d.ReadBool()
+		v.SetBool(b)
+		return err
+	case reflect.Int8:
+		b, err := d.ReadByte()
+		v.SetInt(int64(int8(b)))
+		return noEOF(err)
+	case reflect.Uint8:
+		b, err := d.ReadByte()
+		v.SetUint(uint64(b))
+		return noEOF(err)
+	case reflect.Int16:
+		n, err := d.ReadUint16()
+		v.SetInt(int64(int16(n)))
+		return err
+	case reflect.Uint16:
+		n, err := d.ReadUint16()
+		v.SetUint(uint64(n))
+		return err
+	case reflect.Int32:
+		n, err := d.ReadUint32()
+		v.SetInt(int64(int32(n)))
+		return err
+	case reflect.Uint32:
+		n, err := d.ReadUint32()
+		v.SetUint(uint64(n))
+		return err
+	case reflect.Int64:
+		n, err := d.ReadUint64()
+		v.SetInt(int64(n))
+		return err
+	case reflect.Uint64:
+		n, err := d.ReadUint64()
+		v.SetUint(n)
+		return err
+	case reflect.Float32:
+		f, err := d.ReadFloat32()
+		v.SetFloat(float64(f))
+		return err
+	case reflect.Float64:
+		f, err := d.ReadFloat64()
+		v.SetFloat(f)
+		return err
+	case reflect.String:
+		max := opts.max
+		if max == 0 {
+			max = MaxStringLength
+		}
+		s, err := d.ReadString(max)
+		v.SetString(s)
+		return err
+	case reflect.Slice:
+		if opts.rest {
+			return d.decodeRest(v, opts)
+		}
+		n, err := d.readLength(opts)
+		if err != nil {
+			return err
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 && opts.kind == "" {
+			b, err := d.readBytes(n)
+			if err != nil {
+				return err
+			}
+			v.SetBytes(b)
+			return nil
+		}
+		if n == 0 {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		s := reflect.MakeSlice(v.Type(), 0, 0)
+		elemOpts := fieldOptions{kind: opts.kind, max: opts.max}
+		for i := 0; i < n; i++ {
+			s = reflect.Append(s, reflect.Zero(v.Type().Elem()))
+			if err := d.decode(s.Index(i), elemOpts); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+	case reflect.Array:
+		elemOpts := fieldOptions{kind: opts.kind, max: opts.max}
+		for i := 0; i < v.Len(); i++ {
+			if err := d.decode(v.Index(i), elemOpts); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Struct:
+		fields, err := typeFields(v.Type())
+		if err != nil {
+			return err
+		}
+		for _, f := range fields {
+			if err := d.decode(v.Field(f.index), f.opts); err != nil {
+				return fmt.Errorf("%s.%s: %v", v.Type(), f.name, err)
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("protocol: unsupported type %s", v.Type())
+}
+
+// decodeRest reads the remaining bytes of the input into the byte slice v.
+func (d *Decoder) decodeRest(v reflect.Value, opts fieldOptions) error {
+	if v.Type().Elem().Kind() != reflect.Uint8 || opts.kind != "" {
+		return fmt.Errorf("protocol: rest requires a byte slice, got %s", v.Type())
+	}
+
+	b, err := io.ReadAll(d.r)
+	if err != nil {
+		return err
+	}
+	v.SetBytes(b)
+	return nil
+}
+
+func (d *Decoder) readLength(opts fieldOptions) (int, error) {
+	var n int
+	switch opts.length {
+	case "byte":
+		b, err := d.ReadByte()
+		if err != nil {
+			return 0, noEOF(err)
+		}
+		n = int(b)
+	case "short":
+		v, err := d.ReadUint16()
+		if err != nil {
+			return 0, err
+		}
+		n = int(v)
+	case "int":
+		v, err := d.ReadUint32()
+		if err != nil {
+			return 0, err
+		}
+		n = int(int32(v))
+	default:
+		v, err := d.ReadVarInt()
+		if err != nil {
+			return 0, err
+		}
+		n = int(v)
+	}
+
+	if n < 0 {
+		return 0, fmt.Errorf("protocol: negative array length %d", n)
+	}
+	if opts.max > 0 && n > opts.max && opts.kind != "string" {
+		return 0, fmt.Errorf("protocol: array length %d exceeds maximum of %d", n, opts.max)
+	}
+	// Every element takes at least a byte so a longer array cannot fit in a
+	// packet.
+	if n > MaxPacketLength {
+		return 0, fmt.Errorf("protocol: array length %d exceeds maximum of %d", n, MaxPacketLength)
+	}
+
+	return n, nil
+}
+
+// readBytes reads exactly n bytes, growing the buffer as data arrives so that
+// a bogus length fails when the data runs out instead of allocating the
+// memory up front.
+func (d *Decoder) readBytes(n int) ([]byte, error) {
+	if n <= 1<<16 {
+		b := make([]byte, n)
+		return b, d.ReadFull(b)
+	}
+
+	var buf bytes.Buffer
+	if _, err := io.CopyN(&buf, d.r, int64(n)); err != nil {
+		return nil, noEOF(err)
+	}
+	return buf.Bytes(), nil
+}
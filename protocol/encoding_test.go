@@ -0,0 +1,194 @@
+package protocol
+
+import (
+	"bufio"
+	"bytes"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+type testItem struct {
+	ID    int32 `mc:"varint"`
+	Count int8
+}
+
+type testPacket struct {
+	Bool     bool
+	Short    int16
+	Int      int32
+	Long     int64
+	Float    float32
+	Double   float64
+	VarInt   int32  `mc:"varint"`
+	VarLong  int64  `mc:"varlong"`
+	Name     string `mc:"string,max=16"`
+	Location Position
+	Items    []testItem `mc:",len=byte"`
+	IDs      []int32    `mc:"varint"`
+	Target   *int32     `mc:"varint,optional"`
+	Missing  *string    `mc:",optional"`
+	Ignored  int        `mc:"-"`
+	Data     []byte     `mc:",rest"`
+}
+
+func TestMarshal(t *testing.T) {
+	h := &Handshake{
+		ProtocolVersion: 404,
+		ServerAddress:   "localhost",
+		ServerPort:      25565,
+		NextState:       ClientStateStatus,
+	}
+	expected := []byte{0x94, 0x03, 0x09, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x68, 0x6f, 0x73, 0x74, 0x63, 0xdd, 0x01}
+
+	b, err := Marshal(h)
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	if !bytes.Equal(b, expected) {
+		t.Fatalf("Expected '%+v' to be encoded as '%#02x' got '%#02x'", h, expected, b)
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	target := int32(-300)
+	p := testPacket{
+		Bool:     true,
+		Short:    -2,
+		Int:      1 << 20,
+		Long:     -1 << 40,
+		Float:    1.5,
+		Double:   -0.25,
+		VarInt:   -1,
+		VarLong:  1 << 50,
+		Name:     "Notch",
+		Location: Position{X: -1, Y: 64, Z: 33554431},
+		Items:    []testItem{{ID: 1, Count: 64}, {ID: 280, Count: 1}},
+		IDs:      []int32{1, 128, -1},
+		Target:   &target,
+		Data:     []byte{0xde, 0xad, 0xbe, 0xef},
+	}
+
+	b, err := Marshal(p)
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	var decoded testPacket
+	if err := Unmarshal(bytes.NewReader(b), &decoded); err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	if !reflect.DeepEqual(p, decoded) {
+		t.Fatalf("Expected '%#02x' to decode to '%+v' got '%+v'", b, p, decoded)
+	}
+}
+
+func TestMarshalErrors(t *testing.T) {
+	tests := map[string]interface{}{
+		"string too long": struct {
+			S string `mc:"string,max=2"`
+		}{"abc"},
+		"array too long": struct {
+			A []int8 `mc:",max=1"`
+		}{[]int8{1, 2}},
+		"varint on string": struct {
+			S string `mc:"varint"`
+		}{"abc"},
+		"unknown type": struct {
+			I int32 `mc:"varshort"`
+		}{1},
+		"unsized int": struct {
+			I int
+		}{1},
+		"nil pointer": struct {
+			P *int32
+		}{},
+	}
+
+	for name, test := range tests {
+		if _, err := Marshal(test); err == nil {
+			t.Fatalf("Expected an error for %s", name)
+		}
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	tests := map[string][]byte{
+		"truncated":       []byte{0x00, 0x01},
+		"string too long": []byte{0x11, 0x61, 0x61, 0x61},
+		"invalid boolean": []byte{0x02},
+	}
+
+	var v struct {
+		B bool
+		S string `mc:"string,max=4"`
+	}
+
+	for name, test := range tests {
+		if err := Unmarshal(bytes.NewReader(test), &v); err == nil {
+			t.Fatalf("Expected an error for %s", name)
+		}
+	}
+}
+
+func TestUnmarshalLength(t *testing.T) {
+	tests := map[string]int32{
+		"larger than a packet": 0x7fffffff,
+		"fits in a packet":     MaxPacketLength - 1,
+	}
+
+	for name, length := range tests {
+		data := append(VarInt(length), 0x01, 0x02, 0x03, 0x04)
+
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		var p EncryptionResponse
+		err := p.Decode(bufio.NewReader(bytes.NewReader(data)))
+		runtime.ReadMemStats(&after)
+
+		if err == nil {
+			t.Fatalf("Expected an error for %s", name)
+		}
+		if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1<<20 {
+			t.Fatalf("Expected '%s' to allocate less than 1MiB got '%d' bytes", name, allocated)
+		}
+	}
+}
+
+func TestPosition(t *testing.T) {
+	tests := map[Position]int64{
+		Position{X: 0, Y: 0, Z: 0}:                  0,
+		Position{X: 1, Y: 1, Z: 1}:                  0x4004000001,
+		Position{X: -1, Y: -1, Z: -1}:               -1,
+		Position{X: 18357644, Y: 831, Z: -20882616}: 0x4607630cfec15b48,
+	}
+
+	for test, expected := range tests {
+		if v := test.Pack(); v != expected {
+			t.Fatalf("Expected '%+v' to be packed as '%#x' got '%#x'", test, expected, v)
+		}
+
+		if p := UnpackPosition(expected); p != test {
+			t.Fatalf("Expected '%#x' to unpack to '%+v' got '%+v'", expected, test, p)
+		}
+	}
+}
+
+func BenchmarkMarshal(b *testing.B) {
+	h := &Handshake{ProtocolVersion: 404, ServerAddress: "localhost", ServerPort: 25565, NextState: ClientStateLogin}
+	for n := 0; n < b.N; n++ {
+		Marshal(h)
+	}
+}
+
+func BenchmarkUnmarshal(b *testing.B) {
+	bs, _ := Marshal(&Handshake{ProtocolVersion: 404, ServerAddress: "localhost", ServerPort: 25565, NextState: ClientStateLogin})
+	r := bytes.NewReader(bs)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		r.Reset(bs)
+		Unmarshal(r, &Handshake{})
+	}
+}
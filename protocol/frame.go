@@ -0,0 +1,220 @@
+package protocol
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// MaxPacketLength is the maximum length of a packet, including its ID. It is
+// the largest length that fits in a 3 byte VarInt, as accepted by vanilla.
+// See https://wiki.vg/Protocol#Packet_format for more info.
+const MaxPacketLength = 1<<21 - 1
+
+// ErrPacketTooLarge is returned for packets longer than MaxPacketLength.
+var ErrPacketTooLarge = errors.New("protocol: packet too large")
+
+// Frame is a packet read by a FrameReader.
+type Frame struct {
+	ID int32
+	// Data are the bytes of the packet after the ID.
+	Data []byte
+
+	r  *bytes.Reader
+	br *bufio.Reader
+}
+
+// Reader returns a reader of the frame's data. Reads past the end of the data
+// return io.EOF instead of reading the next packet. Every call returns the
+// same reader.
+func (f *Frame) Reader() *bufio.Reader {
+	if f.br == nil {
+		f.r = bytes.NewReader(f.Data)
+		f.br = bufio.NewReaderSize(f.r, 16)
+	}
+	return f.br
+}
+
+// Remaining returns the number of bytes of the frame's data that have not been
+// read from its Reader.
+func (f *Frame) Remaining() int {
+	if f.br == nil {
+		return len(f.Data)
+	}
+	return f.r.Len() + f.br.Buffered()
+}
+
+// FrameReader reads length prefixed packets.
+type FrameReader struct {
+	r         *bufio.Reader
+	threshold int
+}
+
+// NewFrameReader returns a FrameReader reading from r. When r is a
+// bufio.Reader it is used directly so bytes may be peeked from it between
+// frames.
+func NewFrameReader(r io.Reader) *FrameReader {
+	return &FrameReader{r: bufio.NewReader(r), threshold: -1}
+}
+
+// SetCompression switches to the compressed packet format where packets of at
+// least threshold bytes are compressed. A negative threshold switches back to
+// the uncompressed format.
+// See https://wiki.vg/Protocol#With_compression for more info.
+func (f *FrameReader) SetCompression(threshold int) {
+	f.threshold = threshold
+}
+
+// SetCipher decrypts everything read after the current frame with s.
+func (f *FrameReader) SetCipher(s cipher.Stream) {
+	// Bytes already buffered were sent after the current frame so they are
+	// decrypted too.
+	f.r = bufio.NewReader(cipher.StreamReader{S: s, R: f.r})
+}
+
+// ReadFrame reads exactly the length of the next packet. Packets longer than
+// MaxPacketLength are rejected without reading them.
+func (f *FrameReader) ReadFrame() (*Frame, error) {
+	length, err := ReadVarInt(f.r)
+	if err != nil {
+		return nil, err
+	}
+	if length > MaxPacketLength {
+		return nil, fmt.Errorf("%w: length %d exceeds %d", ErrPacketTooLarge, length, MaxPacketLength)
+	}
+	if length <= 0 {
+		return nil, fmt.Errorf("protocol: invalid packet length %d", length)
+	}
+
+	b := make([]byte, length)
+	if _, err := io.ReadFull(f.r, b); err != nil {
+		return nil, noEOF(err)
+	}
+
+	if f.threshold >= 0 {
+		if b, err = f.decompress(b); err != nil {
+			return nil, err
+		}
+	}
+
+	r := bytes.NewReader(b)
+	id, err := ReadVarInt(r)
+	if err != nil {
+		return nil, fmt.Errorf("protocol: invalid packet ID: %v", noEOF(err))
+	}
+
+	return &Frame{ID: id, Data: b[len(b)-r.Len():]}, nil
+}
+
+// decompress returns the ID and data of a packet in the compressed format.
+func (f *FrameReader) decompress(b []byte) ([]byte, error) {
+	r := bytes.NewReader(b)
+	dataLength, err := ReadVarInt(r)
+	if err != nil {
+		return nil, fmt.Errorf("protocol: invalid data length: %v", noEOF(err))
+	}
+	if dataLength == 0 {
+		return b[len(b)-r.Len():], nil
+	}
+	if dataLength > MaxPacketLength {
+		return nil, fmt.Errorf("%w: data length %d exceeds %d", ErrPacketTooLarge, dataLength, MaxPacketLength)
+	}
+	if dataLength < int32(f.threshold) {
+		return nil, fmt.Errorf("protocol: invalid compressed data length %d", dataLength)
+	}
+
+	zr, err := getZlibReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("protocol: invalid compressed packet: %v", err)
+	}
+	defer putZlibReader(zr)
+
+	data := make([]byte, dataLength)
+	if _, err := io.ReadFull(zr, data); err != nil {
+		return nil, fmt.Errorf("protocol: invalid compressed packet: %v", noEOF(err))
+	}
+	if n, _ := zr.Read(make([]byte, 1)); n > 0 {
+		return nil, fmt.Errorf("protocol: compressed packet is longer than %d bytes", dataLength)
+	}
+	return data, nil
+}
+
+// FrameWriter writes length prefixed packets.
+type FrameWriter struct {
+	w         io.Writer
+	threshold int
+}
+
+// NewFrameWriter returns a FrameWriter writing to w.
+func NewFrameWriter(w io.Writer) *FrameWriter {
+	return &FrameWriter{w: w, threshold: -1}
+}
+
+// SetCompression switches to the compressed packet format where packets of at
+// least threshold bytes are compressed. A negative threshold switches back to
+// the uncompressed format.
+func (f *FrameWriter) SetCompression(threshold int) {
+	f.threshold = threshold
+}
+
+// SetCipher encrypts the following frames with s.
+func (f *FrameWriter) SetCipher(s cipher.Stream) {
+	f.w = cipher.StreamWriter{S: s, W: f.w}
+}
+
+// WriteFrame writes the packet id with its data, prefixed by their length, in
+// a single write.
+func (f *FrameWriter) WriteFrame(id int32, data []byte) error {
+	length := SizeVarInt(uint32(id)) + len(data)
+	if length > MaxPacketLength {
+		return fmt.Errorf("%w: length %d exceeds %d", ErrPacketTooLarge, length, MaxPacketLength)
+	}
+
+	if f.threshold >= 0 {
+		return f.writeCompressed(id, data, length)
+	}
+
+	b := make([]byte, 0, SizeVarInt(uint32(length))+length)
+	b = append(b, VarInt(int32(length))...)
+	b = append(b, VarInt(id)...)
+	b = append(b, data...)
+
+	_, err := f.w.Write(b)
+	return err
+}
+
+// writeCompressed writes the packet in the compressed format. Packets shorter
+// than the threshold are sent uncompressed with a data length of 0.
+func (f *FrameWriter) writeCompressed(id int32, data []byte, dataLength int) error {
+	var body bytes.Buffer
+	if dataLength < f.threshold {
+		body.WriteByte(0x00)
+		body.Write(VarInt(id))
+		body.Write(data)
+	} else {
+		body.Write(VarInt(int32(dataLength)))
+
+		zw := getZlibWriter(&body)
+		zw.Write(VarInt(id))
+		zw.Write(data)
+		err := zw.Close()
+		putZlibWriter(zw)
+		if err != nil {
+			return err
+		}
+	}
+
+	if body.Len() > MaxPacketLength {
+		return fmt.Errorf("%w: compressed length %d exceeds %d", ErrPacketTooLarge, body.Len(), MaxPacketLength)
+	}
+
+	b := make([]byte, 0, SizeVarInt(uint32(body.Len()))+body.Len())
+	b = append(b, VarInt(int32(body.Len()))...)
+	b = append(b, body.Bytes()...)
+
+	_, err := f.w.Write(b)
+	return err
+}
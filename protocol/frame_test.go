@@ -0,0 +1,136 @@
+package protocol
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+func TestFrames(t *testing.T) {
+	tests := map[int32][]byte{
+		0x00: nil,
+		0x01: {0xca, 0xfe},
+		0x80: bytes.Repeat([]byte{0x01}, 300),
+	}
+
+	for id, data := range tests {
+		var buf bytes.Buffer
+		if err := NewFrameWriter(&buf).WriteFrame(id, data); err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+
+		length, _ := ReadVarInt(bytes.NewReader(buf.Bytes()))
+		if expected := SizeVarInt(uint32(id)) + len(data); int(length) != expected {
+			t.Fatalf("Expected length %d got %d", expected, length)
+		}
+
+		frame, err := NewFrameReader(&buf).ReadFrame()
+		if err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+		if frame.ID != id || !bytes.Equal(frame.Data, data) {
+			t.Fatalf("Expected packet %#02x '%#02x' got %#02x '%#02x'", id, data, frame.ID, frame.Data)
+		}
+	}
+}
+
+func TestFrameRemaining(t *testing.T) {
+	r := NewFrameReader(bytes.NewReader([]byte{0x04, 0x00, 0x01, 0x02, 0x03, 0x01, 0x01}))
+
+	frame, err := r.ReadFrame()
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	if n := frame.Remaining(); n != 3 {
+		t.Fatalf("Expected 3 remaining bytes got %d", n)
+	}
+
+	frame.Reader().ReadByte()
+	if n := frame.Remaining(); n != 2 {
+		t.Fatalf("Expected 2 remaining bytes got %d", n)
+	}
+
+	// Reading past the frame must not read the next packet.
+	if _, err := io.ReadFull(frame.Reader(), make([]byte, 3)); err != io.ErrUnexpectedEOF {
+		t.Fatalf("Expected '%v' got '%v'", io.ErrUnexpectedEOF, err)
+	}
+
+	frame, err = r.ReadFrame()
+	if err != nil || frame.ID != 0x01 {
+		t.Fatalf("Expected packet 0x01 got '%+v' '%v'", frame, err)
+	}
+}
+
+func TestFrameLength(t *testing.T) {
+	tests := map[string][]byte{
+		"empty":     {0x00},
+		"too large": VarInt(MaxPacketLength + 1),
+		"truncated": {0x03, 0x00, 0x01},
+	}
+
+	for name, test := range tests {
+		_, err := NewFrameReader(bytes.NewReader(test)).ReadFrame()
+		if err == nil {
+			t.Fatalf("Expected an error for a %s packet", name)
+		}
+		if tooLarge := errors.Is(err, ErrPacketTooLarge); tooLarge != (name == "too large") {
+			t.Fatalf("Expected '%v' to be ErrPacketTooLarge: %t", err, !tooLarge)
+		}
+	}
+
+	if err := NewFrameWriter(ioutil.Discard).WriteFrame(0, make([]byte, MaxPacketLength)); !errors.Is(err, ErrPacketTooLarge) {
+		t.Fatalf("Expected '%v' writing a packet larger than %d bytes got '%v'", ErrPacketTooLarge, MaxPacketLength, err)
+	}
+}
+
+func TestFrameCompression(t *testing.T) {
+	tests := map[int][]byte{
+		-1:  bytes.Repeat([]byte{0x01}, 300),
+		0:   nil,
+		256: bytes.Repeat([]byte{0x01}, 100),
+		64:  bytes.Repeat([]byte{0x01}, 1000),
+	}
+
+	for threshold, data := range tests {
+		var buf bytes.Buffer
+		w := NewFrameWriter(&buf)
+		w.SetCompression(threshold)
+		if err := w.WriteFrame(0x22, data); err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+
+		// The repeated bytes are much shorter once compressed.
+		compressed := threshold >= 0 && 1+len(data) >= threshold
+		if compressed && len(data) > 0 && buf.Len() >= len(data) {
+			t.Fatalf("Expected %d bytes to be compressed with threshold %d got %d bytes", len(data), threshold, buf.Len())
+		}
+
+		r := NewFrameReader(&buf)
+		r.SetCompression(threshold)
+		frame, err := r.ReadFrame()
+		if err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+		if frame.ID != 0x22 || !bytes.Equal(frame.Data, data) {
+			t.Fatalf("Expected packet 0x22 '%#02x' got %#02x '%#02x'", data, frame.ID, frame.Data)
+		}
+	}
+}
+
+func TestFrameCompressionLength(t *testing.T) {
+	tests := map[string][]byte{
+		"below threshold": {0x03, 0x02, 0x78, 0x9c},
+		"not zlib":        {0x04, 0x80, 0x02, 0x00, 0x00},
+		"too large":       append([]byte{0x05}, VarInt(MaxPacketLength+1)...),
+	}
+
+	for name, test := range tests {
+		r := NewFrameReader(bytes.NewReader(test))
+		r.SetCompression(256)
+		if _, err := r.ReadFrame(); err == nil {
+			t.Fatalf("Expected an error for a %s packet", name)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package protocol
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultNamespace is the namespace of an Identifier that does not specify
+// one.
+const DefaultNamespace = "minecraft"
+
+// Identifier is a namespaced location in the form "namespace:path", for
+// example "minecraft:stone". It is encoded as a String(32767).
+// See https://wiki.vg/Protocol#Identifier for more info.
+type Identifier string
+
+// ParseIdentifier validates s and returns it as an Identifier. The namespace
+// defaults to DefaultNamespace when s does not contain one.
+func ParseIdentifier(s string) (Identifier, error) {
+	namespace, path := DefaultNamespace, s
+	if i := strings.IndexByte(s, ':'); i >= 0 {
+		namespace, path = s[:i], s[i+1:]
+	}
+
+	if namespace == "" || !validIdentifier(namespace, false) {
+		return "", fmt.Errorf("invalid identifier namespace %q", namespace)
+	}
+
+	if path == "" || !validIdentifier(path, true) {
+		return "", fmt.Errorf("invalid identifier path %q", path)
+	}
+
+	return Identifier(namespace + ":" + path), nil
+}
+
+// Namespace returns the namespace of the Identifier.
+func (i Identifier) Namespace() string {
+	if n := strings.IndexByte(string(i), ':'); n >= 0 {
+		return string(i[:n])
+	}
+	return DefaultNamespace
+}
+
+// Path returns the path of the Identifier.
+func (i Identifier) Path() string {
+	if n := strings.IndexByte(string(i), ':'); n >= 0 {
+		return string(i[n+1:])
+	}
+	return string(i)
+}
+
+// MarshalProtocol implements the Marshaler interface.
+func (i Identifier) MarshalProtocol(e *Encoder) error {
+	if _, err := ParseIdentifier(string(i)); err != nil {
+		return err
+	}
+	return e.WriteString(string(i))
+}
+
+// UnmarshalProtocol implements the Unmarshaler interface.
+func (i *Identifier) UnmarshalProtocol(d *Decoder) error {
+	s, err := d.ReadString(MaxStringLength)
+	if err != nil {
+		return err
+	}
+
+	id, err := ParseIdentifier(s)
+	if err != nil {
+		return err
+	}
+	*i = id
+
+	return nil
+}
+
+// validIdentifier reports whether s only contains characters allowed in an
+// Identifier. Paths may additionally contain '/'.
+func validIdentifier(s string, path bool) bool {
+	for _, c := range s {
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '_', c == '-', c == '.':
+		case c == '/' && path:
+		default:
+			return false
+		}
+	}
+	return true
+}
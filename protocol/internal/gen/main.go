@@ -0,0 +1,581 @@
+// Command gen generates the packet definitions of the protocol package from
+// a protocol description in the minecraft-data JSON format.
+//
+// Usage:
+//
+//	gen [-out dir] protocol.json...
+//
+// Each description is a version of the protocol. The IDs of the packets of the
+// first one are generated as constants in the server and client packages.
+// The other versions reuse the structs of the previous ones with the same name
+// and layout. A different layout is generated as a new struct named after the
+// protocol version, for example ChatMessage340.
+//
+// Types declared as "native" in the description are implemented by hand in
+// the protocol package, as are types aliasing a primitive type such as
+// "hand": "varint" which are used for enums. Every other type must be a
+// container and is generated as a struct. Packets declared as "native" have
+// a layout that cannot be described by a container and only get their
+// Decode and Encode methods generated.
+//
+// The description supports the following types: varint, varlong, bool, i8,
+// u8, i16, u16, i32, i64, f32, f64, UUID, restBuffer, string or
+// ["string", {"max": n}], ["buffer", {"countType": t}],
+// ["array", {"countType": t, "type": t}] and ["option", t].
+// See https://github.com/PrismarineJS/minecraft-data for more info.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// states are the connection states in the order they are used.
+var states = []string{"handshaking", "status", "login", "play"}
+
+// directions maps the direction of a packet to the package of its IDs.
+var directions = []struct {
+	name, pkg, side, registry, doc string
+}{
+	{"toServer", "server", "serverbound", "ServerPackets", "sent by the client to the server"},
+	{"toClient", "client", "clientbound", "ClientPackets", "sent by the server to the client"},
+}
+
+// primitives maps the primitive types to their Go type and "mc" tag kind.
+var primitives = map[string][2]string{
+	"varint":     {"int32", "varint"},
+	"varlong":    {"int64", "varlong"},
+	"bool":       {"bool", ""},
+	"i8":         {"int8", ""},
+	"u8":         {"uint8", ""},
+	"i16":        {"int16", ""},
+	"u16":        {"uint16", ""},
+	"i32":        {"int32", ""},
+	"i64":        {"int64", ""},
+	"f32":        {"float32", ""},
+	"f64":        {"float64", ""},
+	"string":     {"string", ""},
+	"UUID":       {"uuid.UUID", ""},
+	"restBuffer": {"[]byte", ""},
+}
+
+// countTypes maps the count types of arrays and buffers to the "len" option.
+var countTypes = map[string]string{
+	"varint": "",
+	"i8":     "byte",
+	"u8":     "byte",
+	"i16":    "short",
+	"i32":    "int",
+}
+
+// initialisms are the words written in upper case in Go names.
+var initialisms = map[string]string{
+	"id":   "ID",
+	"ids":  "IDs",
+	"json": "JSON",
+	"nbt":  "NBT",
+	"url":  "URL",
+	"uuid": "UUID",
+}
+
+type protocol struct {
+	Version struct {
+		MinecraftVersion string `json:"minecraftVersion"`
+		Version          int    `json:"version"`
+	} `json:"version"`
+	Types map[string]json.RawMessage `json:"types"`
+
+	states map[string]map[string]*direction
+	names  map[string]string // Go names of the containers
+}
+
+type direction struct {
+	Types map[string]json.RawMessage `json:"types"`
+}
+
+type field struct {
+	Name string          `json:"name"`
+	Type json.RawMessage `json:"type"`
+}
+
+// packet is a packet of a state and direction.
+type packet struct {
+	id       int32
+	name     string // name of the ID constant
+	typeName string // name of the struct
+	native   bool
+	fields   []field
+}
+
+type generator struct {
+	versions []*protocol
+	types    bytes.Buffer      // generated structs and methods
+	structs  map[string]string // fields of the generated structs
+	methods  map[string]bool   // structs with generated methods
+	usesUUID bool
+}
+
+func main() {
+	out := flag.String("out", ".", "directory of the protocol package")
+	flag.Parse()
+	if flag.NArg() == 0 {
+		log.Fatal("usage: gen [-out dir] protocol.json...")
+	}
+
+	g := &generator{
+		structs: make(map[string]string),
+		methods: make(map[string]bool),
+	}
+	var sources []string
+	for _, name := range flag.Args() {
+		b, err := ioutil.ReadFile(name)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		p, err := parse(b)
+		if err != nil {
+			log.Fatalf("%s: %v", name, err)
+		}
+		g.versions = append(g.versions, p)
+		sources = append(sources, filepath.ToSlash(name))
+	}
+	source := strings.Join(sources, ", ")
+
+	files := map[string]func(string) ([]byte, error){
+		"packets_gen.go":        g.packets,
+		"server/packets_gen.go": func(source string) ([]byte, error) { return g.ids(source, directions[0].pkg, directions[0].name) },
+		"client/packets_gen.go": func(source string) ([]byte, error) { return g.ids(source, directions[1].pkg, directions[1].name) },
+	}
+	for name, gen := range files {
+		src, err := gen(source)
+		if err != nil {
+			log.Fatalf("%s: %v", name, err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(*out, name), src, 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func parse(b []byte) (*protocol, error) {
+	p := &protocol{
+		states: make(map[string]map[string]*direction),
+		names:  make(map[string]string),
+	}
+	if err := json.Unmarshal(b, p); err != nil {
+		return nil, err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+	for _, state := range states {
+		s, ok := raw[state]
+		if !ok {
+			return nil, fmt.Errorf("missing state %q", state)
+		}
+
+		var dirs map[string]*direction
+		if err := json.Unmarshal(s, &dirs); err != nil {
+			return nil, fmt.Errorf("%s: %v", state, err)
+		}
+		p.states[state] = dirs
+	}
+	return p, nil
+}
+
+// packets returns the packets of a state and direction sorted by ID.
+func (p *protocol) packets(state, dir string) ([]packet, error) {
+	d, ok := p.states[state][dir]
+	if !ok {
+		return nil, nil
+	}
+
+	// The packet type is a container of a mapper from the ID to the name of
+	// the packet and a switch from the name to the packet type.
+	var root []json.RawMessage
+	if err := json.Unmarshal(d.Types["packet"], &root); err != nil || len(root) != 2 {
+		return nil, fmt.Errorf("%s.%s: invalid packet type", state, dir)
+	}
+	var fields []field
+	if err := json.Unmarshal(root[1], &fields); err != nil || len(fields) != 2 {
+		return nil, fmt.Errorf("%s.%s: invalid packet type", state, dir)
+	}
+
+	var mapper struct {
+		Mappings map[string]string `json:"mappings"`
+	}
+	var switchType struct {
+		Fields map[string]string `json:"fields"`
+	}
+	var args []json.RawMessage
+	if err := json.Unmarshal(fields[0].Type, &args); err != nil || len(args) != 2 {
+		return nil, fmt.Errorf("%s.%s: invalid packet mapper", state, dir)
+	}
+	if err := json.Unmarshal(args[1], &mapper); err != nil {
+		return nil, fmt.Errorf("%s.%s: invalid packet mapper: %v", state, dir, err)
+	}
+	if err := json.Unmarshal(fields[1].Type, &args); err != nil || len(args) != 2 {
+		return nil, fmt.Errorf("%s.%s: invalid packet switch", state, dir)
+	}
+	if err := json.Unmarshal(args[1], &switchType); err != nil {
+		return nil, fmt.Errorf("%s.%s: invalid packet switch: %v", state, dir, err)
+	}
+
+	var packets []packet
+	for idStr, name := range mapper.Mappings {
+		id, err := strconv.ParseInt(idStr, 0, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: invalid packet ID %q", state, dir, idStr)
+		}
+
+		typeName, ok := switchType.Fields[name]
+		if !ok {
+			return nil, fmt.Errorf("%s.%s: no type for packet %q", state, dir, name)
+		}
+		def, ok := d.Types[typeName]
+		if !ok {
+			return nil, fmt.Errorf("%s.%s: missing type %q", state, dir, typeName)
+		}
+
+		pk := packet{
+			id:       int32(id),
+			name:     goName(name),
+			typeName: goName(strings.TrimPrefix(typeName, "packet_")),
+		}
+		if string(def) == `"native"` {
+			pk.native = true
+		} else if pk.fields, err = containerFields(def); err != nil {
+			return nil, fmt.Errorf("%s.%s: %s: %v", state, dir, typeName, err)
+		}
+		packets = append(packets, pk)
+	}
+
+	sort.Slice(packets, func(i, j int) bool { return packets[i].id < packets[j].id })
+	return packets, nil
+}
+
+func containerFields(def json.RawMessage) ([]field, error) {
+	var args []json.RawMessage
+	if err := json.Unmarshal(def, &args); err != nil || len(args) != 2 || string(args[0]) != `"container"` {
+		return nil, fmt.Errorf("expected a container")
+	}
+
+	var fields []field
+	if err := json.Unmarshal(args[1], &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// goName converts a snake_case name of the description to a Go name.
+func goName(s string) string {
+	var b strings.Builder
+	for _, w := range strings.Split(s, "_") {
+		if w == "" {
+			continue
+		}
+		if i, ok := initialisms[w]; ok {
+			b.WriteString(i)
+			continue
+		}
+		b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+	return b.String()
+}
+
+// tag holds the "mc" struct tag of a field.
+type tag struct {
+	kind     string
+	optional bool
+	rest     bool
+	max      int
+	length   string
+}
+
+func (t tag) String() string {
+	opts := []string{t.kind}
+	if t.optional {
+		opts = append(opts, "optional")
+	}
+	if t.rest {
+		opts = append(opts, "rest")
+	}
+	if t.max > 0 {
+		opts = append(opts, "max="+strconv.Itoa(t.max))
+	}
+	if t.length != "" {
+		opts = append(opts, "len="+t.length)
+	}
+
+	s := strings.Join(opts, ",")
+	if s == "" {
+		return ""
+	}
+	return fmt.Sprintf("`mc:%q`", s)
+}
+
+// goType returns the Go type and tag of the type t of the description of
+// version v.
+func (g *generator) goType(v *protocol, t json.RawMessage) (string, tag, error) {
+	var name string
+	if err := json.Unmarshal(t, &name); err == nil {
+		return g.namedType(v, name)
+	}
+
+	var args []json.RawMessage
+	if err := json.Unmarshal(t, &args); err != nil || len(args) != 2 {
+		return "", tag{}, fmt.Errorf("invalid type %s", t)
+	}
+	if err := json.Unmarshal(args[0], &name); err != nil {
+		return "", tag{}, fmt.Errorf("invalid type %s", t)
+	}
+
+	var opts struct {
+		Max       int             `json:"max"`
+		CountType string          `json:"countType"`
+		Type      json.RawMessage `json:"type"`
+	}
+	if name != "option" {
+		if err := json.Unmarshal(args[1], &opts); err != nil {
+			return "", tag{}, fmt.Errorf("invalid %s options: %v", name, err)
+		}
+	}
+
+	switch name {
+	case "string":
+		return "string", tag{kind: "string", max: opts.Max}, nil
+	case "buffer":
+		length, ok := countTypes[opts.CountType]
+		if !ok {
+			return "", tag{}, fmt.Errorf("invalid count type %q", opts.CountType)
+		}
+		return "[]byte", tag{length: length}, nil
+	case "array":
+		length, ok := countTypes[opts.CountType]
+		if !ok {
+			return "", tag{}, fmt.Errorf("invalid count type %q", opts.CountType)
+		}
+		elem, elemTag, err := g.goType(v, opts.Type)
+		if err != nil {
+			return "", tag{}, err
+		}
+		if elemTag.optional || elemTag.rest || elemTag.length != "" {
+			return "", tag{}, fmt.Errorf("unsupported array element %s", opts.Type)
+		}
+		elemTag.length = length
+		return "[]" + elem, elemTag, nil
+	case "option":
+		elem, elemTag, err := g.goType(v, args[1])
+		if err != nil {
+			return "", tag{}, err
+		}
+		if elemTag.optional || elemTag.rest {
+			return "", tag{}, fmt.Errorf("unsupported option of %s", args[1])
+		}
+		elemTag.optional = true
+		return "*" + elem, elemTag, nil
+	}
+	return "", tag{}, fmt.Errorf("unknown type %q", name)
+}
+
+func (g *generator) namedType(v *protocol, name string) (string, tag, error) {
+	if p, ok := primitives[name]; ok {
+		if name == "UUID" {
+			g.usesUUID = true
+		}
+		return p[0], tag{kind: p[1], rest: name == "restBuffer"}, nil
+	}
+
+	def, ok := v.Types[name]
+	if !ok {
+		return "", tag{}, fmt.Errorf("unknown type %q", name)
+	}
+
+	var alias string
+	if err := json.Unmarshal(def, &alias); err == nil {
+		if alias == "native" {
+			return goName(name), tag{}, nil
+		}
+
+		// An alias of a primitive type is an enum declared by hand.
+		p, ok := primitives[alias]
+		if !ok {
+			return "", tag{}, fmt.Errorf("type %q aliases non primitive type %q", name, alias)
+		}
+		return goName(name), tag{kind: p[1]}, nil
+	}
+
+	if n, ok := v.names[name]; ok {
+		return n, tag{}, nil
+	}
+
+	fields, err := containerFields(def)
+	if err != nil {
+		return "", tag{}, fmt.Errorf("type %q: %v", name, err)
+	}
+	n, err := g.structName(v, goName(name), "", fields)
+	if err != nil {
+		return "", tag{}, err
+	}
+	v.names[name] = n
+	return n, tag{}, nil
+}
+
+// structName returns the name of the struct with the fields of version v,
+// generating it with the doc comment doc if no previous version has it.
+func (g *generator) structName(v *protocol, name, doc string, fields []field) (string, error) {
+	var body bytes.Buffer
+	for _, f := range fields {
+		typ, t, err := g.goType(v, f.Type)
+		if err != nil {
+			return "", fmt.Errorf("%s.%s: %v", name, f.Name, err)
+		}
+		fmt.Fprintf(&body, "%s %s %s\n", goName(f.Name), typ, t)
+	}
+
+	for _, n := range []string{name, name + strconv.Itoa(v.Version.Version)} {
+		if s, ok := g.structs[n]; ok {
+			if s == body.String() {
+				return n, nil
+			}
+			continue
+		}
+		g.structs[n] = body.String()
+
+		if doc != "" {
+			if n != name {
+				doc = fmt.Sprintf("%s in protocol %d", doc, v.Version.Version)
+			}
+			fmt.Fprintf(&g.types, "// %s %s.\n", n, doc)
+		}
+		if body.Len() == 0 {
+			fmt.Fprintf(&g.types, "type %s struct{}\n\n", n)
+		} else {
+			fmt.Fprintf(&g.types, "type %s struct {\n%s}\n\n", n, body.Bytes())
+		}
+		return n, nil
+	}
+	return "", fmt.Errorf("%s has another layout in protocol %d", name, v.Version.Version)
+}
+
+// writeMethods generates the Decode and Encode methods of the packet name.
+func (g *generator) writeMethods(name string) {
+	if g.methods[name] {
+		return
+	}
+	g.methods[name] = true
+
+	fmt.Fprintf(&g.types, "func (p *%s) Decode(r *bufio.Reader) error {\nreturn Unmarshal(r, p)\n}\n\n", name)
+	fmt.Fprintf(&g.types, "func (p *%s) Encode() ([]byte, error) {\nreturn Marshal(p)\n}\n\n", name)
+}
+
+// versionName returns the name of the Version of v.
+func versionName(v *protocol) string {
+	return "Version" + strconv.Itoa(v.Version.Version)
+}
+
+// packets generates the packet structs, their methods and the versions.
+func (g *generator) packets(source string) ([]byte, error) {
+	var versions bytes.Buffer
+	var names []string
+
+	for i, v := range g.versions {
+		names = append(names, versionName(v))
+		fmt.Fprintf(&versions, "// %s is protocol %d (%s).\n", versionName(v), v.Version.Version, v.Version.MinecraftVersion)
+		fmt.Fprintf(&versions, "var %s = &Version{\nProtocol: %d,\nName: %q,\n\n", versionName(v), v.Version.Version, v.Version.MinecraftVersion)
+
+		for _, dir := range directions {
+			fmt.Fprintf(&versions, "%s: NewPackets(map[ClientState]map[int32]Handler{\n", dir.registry)
+			for _, state := range states {
+				packets, err := v.packets(state, dir.name)
+				if err != nil {
+					return nil, err
+				}
+				if len(packets) == 0 {
+					continue
+				}
+
+				fmt.Fprintf(&versions, "ClientState%s: {\n", goName(state))
+				for _, pk := range packets {
+					name := pk.typeName
+					if !pk.native {
+						doc := fmt.Sprintf("is the %s packet 0x%02X of the %s state", dir.side, pk.id, goName(state))
+						if name, err = g.structName(v, name, doc, pk.fields); err != nil {
+							return nil, err
+						}
+					}
+					g.writeMethods(name)
+
+					// The first version uses the constants of the ID packages.
+					id := fmt.Sprintf("0x%02X", pk.id)
+					if i == 0 {
+						id = fmt.Sprintf("int32(%s.%s)", dir.pkg, pk.name)
+					}
+					fmt.Fprintf(&versions, "%s: &%s{},\n", id, name)
+				}
+				versions.WriteString("},\n\n")
+			}
+			versions.WriteString("}),\n\n")
+		}
+		versions.WriteString("}\n\n")
+	}
+
+	versions.WriteString("// SupportedVersions are the versions generated from the protocol data.\n")
+	fmt.Fprintf(&versions, "var SupportedVersions = NewVersions(%s)\n\n", strings.Join(names, ", "))
+	versions.WriteString("// DefaultVersion is the version of the packet IDs of the server and client\n// packages.\n")
+	fmt.Fprintf(&versions, "var DefaultVersion = %s\n", names[0])
+
+	var buf bytes.Buffer
+	g.header(&buf, source)
+	buf.WriteString("package protocol\n\nimport (\n\"bufio\"\n\n")
+	if g.usesUUID {
+		buf.WriteString("\"github.com/gofrs/uuid\"\n")
+	}
+	buf.WriteString("\"github.com/JDWardle/gocraft/protocol/client\"\n\"github.com/JDWardle/gocraft/protocol/server\"\n)\n\n")
+	buf.Write(g.types.Bytes())
+	buf.Write(versions.Bytes())
+
+	return format.Source(buf.Bytes())
+}
+
+// ids generates the packet ID constants of a direction of the first version.
+func (g *generator) ids(source, pkg, dir string) ([]byte, error) {
+	var buf bytes.Buffer
+	g.header(&buf, source)
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+
+	for _, state := range states {
+		packets, err := g.versions[0].packets(state, dir)
+		if err != nil {
+			return nil, err
+		}
+		if len(packets) == 0 {
+			continue
+		}
+
+		typ := goName(state)
+		fmt.Fprintf(&buf, "type %s int32\n\nconst (\n", typ)
+		for _, pk := range packets {
+			fmt.Fprintf(&buf, "%s %s = 0x%02X\n", pk.name, typ, pk.id)
+		}
+		buf.WriteString(")\n\n")
+	}
+
+	return format.Source(buf.Bytes())
+}
+
+func (g *generator) header(buf *bytes.Buffer, source string) {
+	fmt.Fprintf(buf, "// Code generated by gen from %s. DO NOT EDIT.\n\n", source)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestGoName(t *testing.T) {
+	tests := map[string]string{
+		"keep_alive":      "KeepAlive",
+		"entity_ids":      "EntityIDs",
+		"json_data":       "JSONData",
+		"line_1":          "Line1",
+		"player_uuid":     "PlayerUUID",
+		"query_block_nbt": "QueryBlockNBT",
+	}
+
+	for name, expected := range tests {
+		if got := goName(name); got != expected {
+			t.Fatalf("Expected '%s' got '%s'", expected, got)
+		}
+	}
+}
+
+func TestGoType(t *testing.T) {
+	v := &protocol{Types: map[string]json.RawMessage{
+		"hand":     json.RawMessage(`"varint"`),
+		"position": json.RawMessage(`"native"`),
+	}}
+	g := &generator{versions: []*protocol{v}}
+
+	tests := map[string]string{
+		`"varint"`:                         "int32 `mc:\"varint\"`",
+		`"restBuffer"`:                     "[]byte `mc:\",rest\"`",
+		`"hand"`:                           "Hand `mc:\"varint\"`",
+		`"position"`:                       "Position ",
+		`["string", {"max": 16}]`:          "string `mc:\"string,max=16\"`",
+		`["buffer", {"countType": "i16"}]`: "[]byte `mc:\",len=short\"`",
+		`["array", {"countType": "varint", "type": "i8"}]`: "[]int8 ",
+		`["array", {"countType": "u8", "type": "varint"}]`: "[]int32 `mc:\"varint,len=byte\"`",
+		`["option", "varint"]`:                             "*int32 `mc:\"varint,optional\"`",
+	}
+
+	for typ, expected := range tests {
+		goType, tag, err := g.goType(v, json.RawMessage(typ))
+		if err != nil {
+			t.Fatalf("Unexpected error for '%s': '%v'", typ, err)
+		}
+		if got := goType + " " + tag.String(); got != expected {
+			t.Fatalf("Expected '%s' got '%s'", expected, got)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"unicode/utf16"
+)
+
+// LegacyString encodes s as a string of the protocol used before 1.7: a Short
+// count of UTF-16 code units followed by the UTF-16BE encoded string.
+// See https://wiki.vg/Server_List_Ping#1.6 for more info.
+func LegacyString(s string) []byte {
+	units := utf16.Encode([]rune(s))
+
+	b := make([]byte, 2+2*len(units))
+	binary.BigEndian.PutUint16(b, uint16(len(units)))
+	for i, u := range units {
+		binary.BigEndian.PutUint16(b[2+2*i:], u)
+	}
+	return b
+}
+
+// ReadLegacyString reads a string of the protocol used before 1.7 of at most
+// max UTF-16 code units.
+func ReadLegacyString(r io.Reader, max int) (string, error) {
+	var n uint16
+	if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+		return "", err
+	}
+	if int(n) > max {
+		return "", fmt.Errorf("protocol: legacy string length %d exceeds %d", n, max)
+	}
+
+	units := make([]uint16, n)
+	if err := binary.Read(r, binary.BigEndian, units); err != nil {
+		return "", noEOF(err)
+	}
+	return string(utf16.Decode(units)), nil
+}
@@ -0,0 +1,34 @@
+package protocol
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLegacyString(t *testing.T) {
+	tests := map[string][]byte{
+		"":           {0x00, 0x00},
+		"§1":         {0x00, 0x02, 0x00, 0xa7, 0x00, 0x31},
+		"MC|":        {0x00, 0x03, 0x00, 'M', 0x00, 'C', 0x00, '|'},
+		"\U0001f600": {0x00, 0x02, 0xd8, 0x3d, 0xde, 0x00},
+	}
+
+	for test, expected := range tests {
+		b := LegacyString(test)
+		if !bytes.Equal(b, expected) {
+			t.Fatalf("Expected '%s' to be encoded as '%#02x' got '%#02x'", test, expected, b)
+		}
+
+		s, err := ReadLegacyString(bytes.NewReader(b), 16)
+		if err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+		if s != test {
+			t.Fatalf("Expected '%#02x' to decode to '%s' got '%s'", b, test, s)
+		}
+	}
+
+	if _, err := ReadLegacyString(bytes.NewReader(LegacyString("MC|PingHost")), 4); err == nil {
+		t.Fatalf("Expected an error reading a string longer than the maximum")
+	}
+}
@@ -0,0 +1,282 @@
+package protocol
+
+import (
+	"fmt"
+
+	"github.com/JDWardle/gocraft/nbt"
+	"github.com/gofrs/uuid"
+)
+
+// MetadataType is the type of an entity metadata value.
+type MetadataType int32
+
+// Metadata types of protocol 404 (1.13.2) and the Go type of their values.
+const (
+	MetadataByte        MetadataType = iota // int8
+	MetadataVarInt                          // int32
+	MetadataFloat                           // float32
+	MetadataString                          // string
+	MetadataChat                            // Chat
+	MetadataOptChat                         // *Chat
+	MetadataSlot                            // Slot
+	MetadataBoolean                         // bool
+	MetadataRotation                        // Rotation
+	MetadataPosition                        // Position
+	MetadataOptPosition                     // *Position
+	MetadataDirection                       // Direction
+	MetadataOptUUID                         // *uuid.UUID
+	MetadataOptBlockID                      // int32, 0 when absent
+	MetadataNBT                             // nbt.Compound
+	MetadataParticle                        // Particle
+)
+
+// metadataEnd marks the end of the entity metadata.
+const metadataEnd = 0xFF
+
+// Rotation is the rotation of an entity around each axis in degrees.
+type Rotation struct {
+	X, Y, Z float32
+}
+
+// Direction is a block face.
+type Direction int32
+
+const (
+	Down Direction = iota
+	Up
+	North
+	South
+	West
+	East
+)
+
+// MetadataEntry is a single value of an entity's metadata.
+type MetadataEntry struct {
+	Index uint8
+	Type  MetadataType
+	Value interface{}
+}
+
+// Metadata is the metadata of an entity. The meaning of each index depends on
+// the type of entity.
+// See https://wiki.vg/Entity_metadata for more info.
+type Metadata []MetadataEntry
+
+// Get returns the entry at index.
+func (m Metadata) Get(index uint8) (MetadataEntry, bool) {
+	for _, e := range m {
+		if e.Index == index {
+			return e, true
+		}
+	}
+	return MetadataEntry{}, false
+}
+
+// Set returns m with the entry at index replaced or added.
+func (m Metadata) Set(index uint8, t MetadataType, value interface{}) Metadata {
+	for i := range m {
+		if m[i].Index == index {
+			m[i] = MetadataEntry{Index: index, Type: t, Value: value}
+			return m
+		}
+	}
+	return append(m, MetadataEntry{Index: index, Type: t, Value: value})
+}
+
+// MarshalProtocol implements the Marshaler interface. Returns an error if the
+// Go type of a value does not match its MetadataType.
+func (m Metadata) MarshalProtocol(e *Encoder) error {
+	for _, entry := range m {
+		if entry.Index == metadataEnd {
+			return fmt.Errorf("protocol: metadata index %d is reserved", metadataEnd)
+		}
+
+		if err := e.WriteByte(entry.Index); err != nil {
+			return err
+		}
+		if err := e.WriteVarInt(int32(entry.Type)); err != nil {
+			return err
+		}
+		if err := entry.encodeValue(e); err != nil {
+			return fmt.Errorf("protocol: metadata index %d: %v", entry.Index, err)
+		}
+	}
+	return e.WriteByte(metadataEnd)
+}
+
+func (entry MetadataEntry) encodeValue(e *Encoder) error {
+	typeError := fmt.Errorf("value of type %T is not valid for metadata type %d", entry.Value, entry.Type)
+
+	switch entry.Type {
+	case MetadataByte:
+		if v, ok := entry.Value.(int8); ok {
+			return e.WriteByte(byte(v))
+		}
+	case MetadataVarInt, MetadataOptBlockID:
+		if v, ok := entry.Value.(int32); ok {
+			return e.WriteVarInt(v)
+		}
+	case MetadataFloat:
+		if v, ok := entry.Value.(float32); ok {
+			return e.WriteFloat32(v)
+		}
+	case MetadataString:
+		if v, ok := entry.Value.(string); ok {
+			return e.WriteString(v)
+		}
+	case MetadataChat:
+		if v, ok := entry.Value.(Chat); ok {
+			return v.MarshalProtocol(e)
+		}
+	case MetadataOptChat:
+		if v, ok := entry.Value.(*Chat); ok {
+			if err := e.WriteBool(v != nil); err != nil || v == nil {
+				return err
+			}
+			return v.MarshalProtocol(e)
+		}
+	case MetadataSlot:
+		if v, ok := entry.Value.(Slot); ok {
+			return v.MarshalProtocol(e)
+		}
+	case MetadataBoolean:
+		if v, ok := entry.Value.(bool); ok {
+			return e.WriteBool(v)
+		}
+	case MetadataRotation:
+		if v, ok := entry.Value.(Rotation); ok {
+			return e.Encode(v)
+		}
+	case MetadataPosition:
+		if v, ok := entry.Value.(Position); ok {
+			return v.MarshalProtocol(e)
+		}
+	case MetadataOptPosition:
+		if v, ok := entry.Value.(*Position); ok {
+			if err := e.WriteBool(v != nil); err != nil || v == nil {
+				return err
+			}
+			return v.MarshalProtocol(e)
+		}
+	case MetadataDirection:
+		if v, ok := entry.Value.(Direction); ok {
+			return e.WriteVarInt(int32(v))
+		}
+	case MetadataOptUUID:
+		if v, ok := entry.Value.(*uuid.UUID); ok {
+			if err := e.WriteBool(v != nil); err != nil || v == nil {
+				return err
+			}
+			_, err := e.Write(v.Bytes())
+			return err
+		}
+	case MetadataNBT:
+		if v, ok := entry.Value.(nbt.Compound); ok {
+			if v == nil {
+				return e.WriteByte(byte(nbt.TagEnd))
+			}
+			return nbt.NewEncoder(e).Encode("", v)
+		}
+	case MetadataParticle:
+		if v, ok := entry.Value.(Particle); ok {
+			return v.MarshalProtocol(e)
+		}
+	default:
+		return fmt.Errorf("unknown metadata type %d", entry.Type)
+	}
+
+	return typeError
+}
+
+// UnmarshalProtocol implements the Unmarshaler interface.
+func (m *Metadata) UnmarshalProtocol(d *Decoder) error {
+	*m = nil
+
+	for {
+		index, err := d.ReadByte()
+		if err != nil {
+			return noEOF(err)
+		}
+		if index == metadataEnd {
+			return nil
+		}
+
+		t, err := d.ReadVarInt()
+		if err != nil {
+			return err
+		}
+
+		entry := MetadataEntry{Index: index, Type: MetadataType(t)}
+		if entry.Value, err = decodeMetadataValue(d, entry.Type); err != nil {
+			return fmt.Errorf("protocol: metadata index %d: %v", index, err)
+		}
+		*m = append(*m, entry)
+	}
+}
+
+func decodeMetadataValue(d *Decoder, t MetadataType) (interface{}, error) {
+	switch t {
+	case MetadataByte:
+		b, err := d.ReadByte()
+		return int8(b), noEOF(err)
+	case MetadataVarInt, MetadataOptBlockID:
+		return d.ReadVarInt()
+	case MetadataFloat:
+		return d.ReadFloat32()
+	case MetadataString:
+		return d.ReadString(MaxStringLength)
+	case MetadataChat:
+		var c Chat
+		err := c.UnmarshalProtocol(d)
+		return c, err
+	case MetadataOptChat:
+		present, err := d.ReadBool()
+		if err != nil || !present {
+			return (*Chat)(nil), err
+		}
+		c := &Chat{}
+		return c, c.UnmarshalProtocol(d)
+	case MetadataSlot:
+		var s Slot
+		err := s.UnmarshalProtocol(d)
+		return s, err
+	case MetadataBoolean:
+		return d.ReadBool()
+	case MetadataRotation:
+		var r Rotation
+		err := d.Decode(&r)
+		return r, err
+	case MetadataPosition:
+		var p Position
+		err := p.UnmarshalProtocol(d)
+		return p, err
+	case MetadataOptPosition:
+		present, err := d.ReadBool()
+		if err != nil || !present {
+			return (*Position)(nil), err
+		}
+		p := &Position{}
+		return p, p.UnmarshalProtocol(d)
+	case MetadataDirection:
+		v, err := d.ReadVarInt()
+		return Direction(v), err
+	case MetadataOptUUID:
+		present, err := d.ReadBool()
+		if err != nil || !present {
+			return (*uuid.UUID)(nil), err
+		}
+		u := &uuid.UUID{}
+		return u, d.ReadFull(u[:])
+	case MetadataNBT:
+		var tag nbt.Compound
+		if _, err := nbt.NewDecoder(d).Decode(&tag); err != nil && err != nbt.ErrEnd {
+			return nil, err
+		}
+		return tag, nil
+	case MetadataParticle:
+		var p Particle
+		err := p.UnmarshalProtocol(d)
+		return p, err
+	}
+	return nil, fmt.Errorf("unknown metadata type %d", t)
+}
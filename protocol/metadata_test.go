@@ -0,0 +1,128 @@
+package protocol
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/JDWardle/gocraft/nbt"
+	"github.com/gofrs/uuid"
+)
+
+func TestMetadata(t *testing.T) {
+	name := Text("Grumm")
+	home := Position{X: 1, Y: 2, Z: 3}
+	owner := uuid.Must(uuid.FromString("069a79f4-44e9-4726-a5be-fca90e38aaf5"))
+
+	m := Metadata{}.
+		Set(0, MetadataByte, int8(0x20)).
+		Set(1, MetadataVarInt, int32(300)).
+		Set(2, MetadataFloat, float32(20)).
+		Set(3, MetadataString, "text").
+		Set(4, MetadataChat, Text("chat")).
+		Set(5, MetadataOptChat, &name).
+		Set(6, MetadataSlot, Slot{Present: true, ItemID: 1, Count: 1}).
+		Set(7, MetadataBoolean, true).
+		Set(8, MetadataRotation, Rotation{X: 1, Y: 2, Z: 3}).
+		Set(9, MetadataPosition, home).
+		Set(10, MetadataOptPosition, (*Position)(nil)).
+		Set(11, MetadataDirection, East).
+		Set(12, MetadataOptUUID, &owner).
+		Set(13, MetadataOptBlockID, int32(0)).
+		Set(14, MetadataNBT, nbt.Compound{"a": int8(1)}).
+		Set(15, MetadataParticle, Particle{ID: ParticleDust, Red: 1, Scale: 1}).
+		Set(16, MetadataOptChat, (*Chat)(nil)).
+		Set(17, MetadataOptPosition, &home)
+
+	b, err := Marshal(m)
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	if b[len(b)-1] != 0xff {
+		t.Fatalf("Expected metadata to end with '0xff' got '%#02x'", b[len(b)-1])
+	}
+
+	var decoded Metadata
+	if err := Unmarshal(bytes.NewReader(b), &decoded); err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	if !reflect.DeepEqual(m, decoded) {
+		t.Fatalf("Expected '%#02x' to decode to '%+v' got '%+v'", b, m, decoded)
+	}
+
+	if e, ok := decoded.Get(11); !ok || e.Value != East {
+		t.Fatalf("Expected index 11 to be '%d' got '%+v'", East, e)
+	}
+}
+
+func TestMetadataErrors(t *testing.T) {
+	tests := map[string]Metadata{
+		"wrong type":    Metadata{}.Set(0, MetadataByte, int32(1)),
+		"unknown type":  Metadata{}.Set(0, 16, int32(1)),
+		"reserved":      Metadata{}.Set(0xff, MetadataByte, int8(1)),
+		"nil interface": Metadata{}.Set(0, MetadataString, nil),
+	}
+
+	for name, test := range tests {
+		if _, err := Marshal(test); err == nil {
+			t.Fatalf("Expected an error for %s", name)
+		}
+	}
+
+	decodeTests := map[string][]byte{
+		"missing end":  []byte{0x00, 0x00, 0x01},
+		"unknown type": []byte{0x00, 0x10, 0x00, 0xff},
+		"bad boolean":  []byte{0x00, 0x07, 0x02, 0xff},
+	}
+
+	for name, test := range decodeTests {
+		var m Metadata
+		if err := Unmarshal(bytes.NewReader(test), &m); err == nil {
+			t.Fatalf("Expected an error decoding %s", name)
+		}
+	}
+}
+
+func TestParticle(t *testing.T) {
+	tests := map[string]Particle{
+		"no data":      Particle{ID: 0},
+		"block":        Particle{ID: ParticleBlock, BlockState: 1},
+		"dust":         Particle{ID: ParticleDust, Red: 1, Green: 0.5, Blue: 0.25, Scale: 2},
+		"falling dust": Particle{ID: ParticleFallingDust, BlockState: 9},
+		"item":         Particle{ID: ParticleItem, Item: Slot{Present: true, ItemID: 1, Count: 1}},
+	}
+
+	for name, test := range tests {
+		b, err := Marshal(test)
+		if err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+
+		var p Particle
+		if err := Unmarshal(bytes.NewReader(b), &p); err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+
+		if !reflect.DeepEqual(p, test) {
+			t.Fatalf("Expected %s particle '%#02x' to decode to '%+v' got '%+v'", name, b, test, p)
+		}
+	}
+}
+
+func FuzzMetadata(f *testing.F) {
+	f.Add([]byte{0xff})
+	f.Add([]byte{0x00, 0x00, 0x20, 0x01, 0x01, 0xac, 0x02, 0xff})
+	f.Add([]byte{0x02, 0x03, 0x04, 0x74, 0x65, 0x78, 0x74, 0x03, 0x05, 0x01, 0x02, 0x22, 0x61, 0x22, 0xff})
+	f.Add([]byte{0x05, 0x06, 0x01, 0x01, 0x01, 0x00, 0x06, 0x0f, 0x0b, 0x3f, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x3f, 0x80, 0x00, 0x00, 0xff})
+	f.Add([]byte{0x07, 0x0c, 0x01, 0x06, 0x9a, 0x79, 0xf4, 0x44, 0xe9, 0x47, 0x26, 0xa5, 0xbe, 0xfc, 0xa9, 0x0e, 0x38, 0xaa, 0xf5, 0x08, 0x0e, 0x0a, 0x00, 0x00, 0x00, 0xff})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var m Metadata
+		if err := Unmarshal(bytes.NewReader(data), &m); err != nil {
+			return
+		}
+		roundTrip(t, m, &Metadata{})
+	})
+}
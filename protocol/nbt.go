@@ -0,0 +1,27 @@
+package protocol
+
+import (
+	"github.com/JDWardle/gocraft/nbt"
+)
+
+// NBT is an NBT compound sent as a packet field. A nil NBT is sent as a
+// TAG_End.
+type NBT nbt.Compound
+
+// MarshalProtocol implements the Marshaler interface.
+func (n NBT) MarshalProtocol(e *Encoder) error {
+	if n == nil {
+		return e.WriteByte(byte(nbt.TagEnd))
+	}
+	return nbt.NewEncoder(e).Encode("", nbt.Compound(n))
+}
+
+// UnmarshalProtocol implements the Unmarshaler interface.
+func (n *NBT) UnmarshalProtocol(d *Decoder) error {
+	var tag nbt.Compound
+	if _, err := nbt.NewDecoder(d).Decode(&tag); err != nil && err != nbt.ErrEnd {
+		return err
+	}
+	*n = NBT(tag)
+	return nil
+}
@@ -2,106 +2,177 @@ package protocol
 
 import (
 	"bufio"
-	"errors"
+	"reflect"
+	"strings"
 	"sync"
 )
 
+// The packets of the supported versions are generated from their description
+// in data, the first one is the DefaultVersion. Only 1.13.2 is described so
+// far, other versions such as 1.12.2 (340), 1.13.1 (401) or 1.14 (477) are
+// supported by adding their description to the command below.
+//go:generate go run ./internal/gen data/1.13.2/protocol.json
+
+// Handler is implemented by every packet. Decode reads the packet's fields,
+// without the length and ID, from r and Encode returns them.
 type Handler interface {
 	Decode(r *bufio.Reader) error
 	Encode() ([]byte, error)
 }
 
+// PacketName returns the name shared by the layouts of the packet h in every
+// version, the name of its type without the protocol version suffix, e.g.
+// ChatMessage for ChatMessage340.
+func PacketName(h Handler) string {
+	return packetName(reflect.TypeOf(h))
+}
+
+func packetName(t reflect.Type) string {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return strings.TrimRight(t.Name(), "0123456789")
+}
+
+// Packets maps the packet IDs of each ClientState to their packet type.
 type Packets struct {
-	m map[ClientState]map[int32]Handler
+	m     map[ClientState]map[int32]reflect.Type
+	ids   map[ClientState]map[reflect.Type]int32
+	names map[ClientState]map[string]int32
 	sync.RWMutex
 }
 
-func (p Packets) GetPacket(clientState ClientState, id int32) (bool, Handler) {
-	defer p.RUnlock()
+// NewPackets returns the registry of the packets in m. The packets are only
+// used for their type, GetPacket always returns new ones.
+func NewPackets(m map[ClientState]map[int32]Handler) *Packets {
+	p := &Packets{
+		m:     make(map[ClientState]map[int32]reflect.Type),
+		ids:   make(map[ClientState]map[reflect.Type]int32),
+		names: make(map[ClientState]map[string]int32),
+	}
+	for state, packets := range m {
+		for id, h := range packets {
+			p.Register(state, id, h)
+		}
+	}
+	return p
+}
+
+// Register adds the type of h as the packet id of clientState, replacing the
+// previous packet with that ID.
+func (p *Packets) Register(clientState ClientState, id int32, h Handler) {
+	p.Lock()
+	defer p.Unlock()
 
-	if h, ok := p.m[clientState][id]; ok {
-		p.RUnlock()
-		return ok, h
+	if p.m[clientState] == nil {
+		p.m[clientState] = make(map[int32]reflect.Type)
+		p.ids[clientState] = make(map[reflect.Type]int32)
+		p.names[clientState] = make(map[string]int32)
+	}
+	if old, ok := p.m[clientState][id]; ok {
+		delete(p.ids[clientState], old)
+		delete(p.names[clientState], packetName(old))
 	}
 
-	p.RUnlock()
-	return false, nil
+	t := reflect.TypeOf(h)
+	p.m[clientState][id] = t
+	p.ids[clientState][t] = id
+	p.names[clientState][packetName(t)] = id
 }
 
-var ServerPackets = Packets{
-	m: map[ClientState]map[int32]Handler{
-		ClientStateHandshaking: {
-			0x00: HandshakeRequest{},
-		},
-
-		ClientStateStatus: {
-			0x00: HandshakeRequest{},
-			0x01: HandshakeRequest{},
-		},
-	},
+// GetPacket returns a new zero packet for the ID in clientState.
+func (p *Packets) GetPacket(clientState ClientState, id int32) (bool, Handler) {
+	p.RLock()
+	t, ok := p.m[clientState][id]
+	p.RUnlock()
+
+	if !ok {
+		return false, nil
+	}
+	if t.Kind() == reflect.Ptr {
+		return true, reflect.New(t.Elem()).Interface().(Handler)
+	}
+	return true, reflect.Zero(t).Interface().(Handler)
 }
 
-var ClientPackets = Packets{
-	m: map[ClientState]map[int32]Handler{
-		ClientStateStatus: {
-			0x00: HandshakeRequest{},
-			0x01: HandshakeRequest{},
-		},
-	},
+// ID returns the packet ID of h in clientState.
+func (p *Packets) ID(clientState ClientState, h Handler) (int32, bool) {
+	p.RLock()
+	defer p.RUnlock()
+
+	id, ok := p.ids[clientState][reflect.TypeOf(h)]
+	return id, ok
 }
 
-type HandshakeRequest struct{}
+// IDByName returns the ID of the packet named name in clientState, see
+// PacketName.
+func (p *Packets) IDByName(clientState ClientState, name string) (int32, bool) {
+	p.RLock()
+	defer p.RUnlock()
+
+	id, ok := p.names[clientState][name]
+	return id, ok
+}
 
-func (h HandshakeRequest) Decode(r *bufio.Reader) error {
-	return errors.New("not implemented")
+// ConvertPacket copies the fields of src to the fields of dst with the same
+// name, for two layouts of a packet in different versions. Numbers are
+// converted to the type of dst, structs, slices and pointers field by field.
+// The other fields of dst are left unchanged.
+func ConvertPacket(dst, src Handler) {
+	convertValue(reflect.ValueOf(dst).Elem(), reflect.ValueOf(src).Elem())
 }
 
-func (h HandshakeRequest) Encode() ([]byte, error) {
-	return nil, errors.New("not implemented")
+func convertValue(dst, src reflect.Value) {
+	if src.Type().AssignableTo(dst.Type()) {
+		dst.Set(src)
+		return
+	}
+	if isNumber(dst.Kind()) && isNumber(src.Kind()) {
+		dst.Set(src.Convert(dst.Type()))
+		return
+	}
+	if dst.Kind() != src.Kind() {
+		return
+	}
+
+	switch dst.Kind() {
+	case reflect.Struct:
+		for i := 0; i < dst.NumField(); i++ {
+			f := dst.Type().Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			if v := src.FieldByName(f.Name); v.IsValid() && v.CanInterface() {
+				convertValue(dst.Field(i), v)
+			}
+		}
+	case reflect.Ptr:
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return
+		}
+		v := reflect.New(dst.Type().Elem())
+		convertValue(v.Elem(), src.Elem())
+		dst.Set(v)
+	case reflect.Slice:
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return
+		}
+		v := reflect.MakeSlice(dst.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			convertValue(v.Index(i), src.Index(i))
+		}
+		dst.Set(v)
+	case reflect.Array:
+		for i := 0; i < dst.Len() && i < src.Len(); i++ {
+			convertValue(dst.Index(i), src.Index(i))
+		}
+	case reflect.Bool, reflect.String:
+		dst.Set(src.Convert(dst.Type()))
+	}
 }
 
-// type Packet struct {
-// 	ID     int32
-// 	Length int32
-
-// 	*bufio.Reader
-// }
-
-// func NewPacket(r io.Reader) (*Packet, error) {
-// 	br := bufio.NewReader(r)
-
-// 	length, err := ReadVarInt(br)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-
-// 	id, err := ReadVarInt(br)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-
-// 	return &Packet{
-// 		ID:     id,
-// 		Length: length,
-// 		Reader: br,
-// 	}, nil
-// }
-
-// var ClientPackets = map[ClientState]map[int32]Handler{
-// 	ClientStateStatus: {
-// 		// 0x00: PacketHandler{},
-// 	},
-// 	ClientStateLogin: {
-// 		// 0x00: PacketHandler{},
-// 	},
-// 	ClientStatePlay: {
-// 		// 0x00: PacketHandler{},
-// 	},
-// }
-
-// var ServerPackets = map[ClientState]map[int32]Handler{
-// 	ClientStateHandsaking: {},
-// 	ClientStateStatus:     {},
-// 	ClientStateLogin:      {},
-// 	ClientStatePlay:       {},
-// }
+func isNumber(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Float64
+}
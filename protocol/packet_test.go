@@ -0,0 +1,180 @@
+package protocol
+
+import (
+	"bufio"
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/JDWardle/gocraft/protocol/client"
+	"github.com/JDWardle/gocraft/protocol/server"
+)
+
+func TestServerPackets(t *testing.T) {
+	testPackets(t, ServerPackets)
+
+	if n := len(ServerPackets.m[ClientStatePlay]); n != int(server.UseItem)+1 {
+		t.Fatalf("Expected %d play packets got %d", server.UseItem+1, n)
+	}
+}
+
+func TestClientPackets(t *testing.T) {
+	testPackets(t, ClientPackets)
+
+	if n := len(ClientPackets.m[ClientStatePlay]); n != int(client.Tags)+1 {
+		t.Fatalf("Expected %d play packets got %d", client.Tags+1, n)
+	}
+}
+
+// testPackets checks that every packet of the registry has a unique type and
+// that GetPacket returns new packets.
+func testPackets(t *testing.T, registry *Packets) {
+	registry.RLock()
+	registered := registry.m
+	registry.RUnlock()
+
+	for state, packets := range registered {
+		for id, typ := range packets {
+			ok, p := registry.GetPacket(state, id)
+			if !ok {
+				t.Fatalf("Expected packet %#02x of state %d to be registered", id, state)
+			}
+			if reflect.TypeOf(p) != typ {
+				t.Fatalf("Expected packet %#02x of state %d to be '%s' got '%T'", id, state, typ, p)
+			}
+
+			_, again := registry.GetPacket(state, id)
+			// Pointers to zero sized values may be equal.
+			if typ.Elem().Size() > 0 && p == again {
+				t.Fatalf("Expected a new '%T' for each call of GetPacket", p)
+			}
+
+			if got, ok := registry.ID(state, p); !ok || got != id {
+				t.Fatalf("Expected ID of '%T' to be %#02x got %#02x", p, id, got)
+			}
+			if got, ok := registry.IDByName(state, PacketName(p)); !ok || got != id {
+				t.Fatalf("Expected ID of '%s' to be %#02x got %#02x", PacketName(p), id, got)
+			}
+		}
+	}
+}
+
+// ChatMessage340 is a Chat Message with another layout.
+type ChatMessage340 struct {
+	Message  string
+	Position int8
+	Items    []Slot
+}
+
+func (p *ChatMessage340) Decode(r *bufio.Reader) error { return Unmarshal(r, p) }
+func (p *ChatMessage340) Encode() ([]byte, error)      { return Marshal(p) }
+
+func TestPacketName(t *testing.T) {
+	tests := map[Handler]string{
+		&ChatMessage{}:    "ChatMessage",
+		&ChatMessage340{}: "ChatMessage",
+		&KeepAlive{}:      "KeepAlive",
+	}
+
+	for h, expected := range tests {
+		if got := PacketName(h); got != expected {
+			t.Fatalf("Expected '%s' got '%s'", expected, got)
+		}
+	}
+}
+
+func TestConvertPacket(t *testing.T) {
+	var message ChatMessage
+	ConvertPacket(&message, &ChatMessage340{Message: "hi", Position: 1})
+	if message.Message != "hi" {
+		t.Fatalf("Expected '%s' got '%s'", "hi", message.Message)
+	}
+
+	var old ChatMessage340
+	ConvertPacket(&old, &message)
+	if old.Message != "hi" || old.Position != 0 {
+		t.Fatalf("Expected '%+v' got '%+v'", ChatMessage340{Message: "hi"}, old)
+	}
+
+	type item struct {
+		Present bool
+		ItemID  int64
+	}
+	var items struct {
+		Items []item
+	}
+	convertValue(reflect.ValueOf(&items).Elem(), reflect.ValueOf(ChatMessage340{Items: []Slot{{Present: true, ItemID: 3}}}))
+	if len(items.Items) != 1 || items.Items[0] != (item{Present: true, ItemID: 3}) {
+		t.Fatalf("Expected '%+v' got '%+v'", []item{{Present: true, ItemID: 3}}, items.Items)
+	}
+}
+
+func TestServerboundPackets(t *testing.T) {
+	testPacketEncoding(t, map[Handler][]byte{
+		&UseEntity{Target: 1, Type: Attack}:                                            {0x01, 0x01},
+		&UseEntity{Target: 1, Type: Interact, Hand: OffHand}:                           {0x01, 0x00, 0x01},
+		&UseEntity{Target: 1, Type: InteractAt, TargetY: 2}:                            {0x01, 0x02, 0, 0, 0, 0, 0x40, 0, 0, 0, 0, 0, 0, 0, 0x00},
+		&AdvancementTab{Action: ClosedScreen}:                                          {0x01},
+		&AdvancementTab{Action: OpenedTab, TabID: "a:b"}:                               {0x00, 0x03, 'a', ':', 'b'},
+		&RecipeBookData{Type: RecipeBookStates, SmeltingRecipeBookOpen: true}:          {0x01, 0x00, 0x00, 0x01, 0x00},
+		&RecipeBookData{Type: DisplayedRecipe, RecipeID: "minecraft:furnace"}:          append([]byte{0x00, 0x11}, "minecraft:furnace"...),
+		&LoginPluginResponse{MessageID: 2, Successful: true, Data: []byte{0xca, 0xfe}}: {0x02, 0x01, 0xca, 0xfe},
+		&ClientSettings{Locale: "en_us", ViewDistance: 10, ChatColors: true, DisplayedSkinParts: 0x7f, MainHand: MainHandRight}: {
+			0x05, 'e', 'n', '_', 'u', 's', 0x0a, 0x00, 0x01, 0x7f, 0x01,
+		},
+	})
+}
+
+func TestClientboundPackets(t *testing.T) {
+	displayName := Text("Notch")
+	achieved := int64(1546300800000)
+	testPacketEncoding(t, map[Handler][]byte{
+		&BossBar{Action: BossBarUpdateHealth, Health: 1}:                                                                        {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x02, 0x3f, 0x80, 0, 0},
+		&BossBar{Action: BossBarRemove}:                                                                                         {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x01},
+		&CombatEvent{Event: EndCombat, Duration: 20, EntityID: 1}:                                                               {0x01, 0x14, 0, 0, 0, 0x01},
+		&Title{Action: SetTimesAndDisplay, FadeIn: 10, Stay: 70, FadeOut: 20}:                                                   {0x03, 0, 0, 0, 0x0a, 0, 0, 0, 0x46, 0, 0, 0, 0x14},
+		&Title{Action: ResetTitle}:                                                                                              {0x05},
+		&StopSound{Flags: StopSoundSource, Source: 2}:                                                                           {0x01, 0x02},
+		&UpdateScore{EntityName: "a", Action: ScoreRemove, ObjectiveName: "b"}:                                                  {0x01, 'a', 0x01, 0x01, 'b'},
+		&WorldBorder{Action: WorldBorderSetWarningBlocks, WarningBlocks: 5}:                                                     {0x05, 0x05},
+		&FacePlayer{FeetEyes: AnchorEyes, IsEntity: true, EntityID: 3}:                                                          {0x01, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x01, 0x03, 0x00},
+		&UnlockRecipes{Action: InitRecipes, RecipeIDs: []Identifier{"minecraft:a"}, InitRecipeIDs: []Identifier{"minecraft:b"}}: append(append(append([]byte{0x00, 0, 0, 0, 0, 0x01, 0x0b}, "minecraft:a"...), 0x01, 0x0b), "minecraft:b"...),
+		&OpenWindow{WindowID: 1, WindowType: "EntityHorse", WindowTitle: Text("a"), NumberOfSlots: 2, EntityID: 7}:              append(append([]byte{0x01, 0x0b}, "EntityHorse"...), append(append([]byte{0x0c}, `{"text":"a"}`...), 0x02, 0, 0, 0, 0x07)...),
+		&MapData{ItemDamage: 1, Scale: 2, Icons: []MapIcon{{Type: 1}}}:                                                          {0x01, 0x02, 0x00, 0x01, 0x01, 0, 0, 0, 0x00, 0x00},
+		&ClientboundParticle{Particle: Particle{ID: ParticleBlock, BlockState: 1}, ParticleCount: 1}:                            append(append([]byte{0, 0, 0, 0x03, 0x00}, make([]byte, 28)...), 0, 0, 0, 0x01, 0x01),
+		&PlayerInfo{Action: UpdateLatency, Players: []PlayerInfoEntry{{Ping: 100}}}:                                             {0x02, 0x01, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x64},
+		&PlayerInfo{Action: UpdateDisplayName, Players: []PlayerInfoEntry{{DisplayName: &displayName}}}:                         append([]byte{0x03, 0x01, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x01, 0x10}, `{"text":"Notch"}`...),
+		&Teams{TeamName: "t", Mode: TeamAddEntities, Entities: []string{"a"}}:                                                   {0x01, 't', 0x03, 0x01, 0x01, 'a'},
+		&DeclareCommands{Nodes: []CommandNode{
+			{Flags: CommandNodeRoot, Children: []int32{1}},
+			{Flags: CommandNodeArgument | CommandNodeExecutable, Name: "n", Parser: "brigadier:integer", Properties: CommandProperties{Flags: 0x01, Min: 1}},
+		}}: append(append([]byte{0x02, 0x00, 0x01, 0x01, 0x06, 0x00, 0x01, 'n', 0x11}, "brigadier:integer"...), 0x01, 0, 0, 0, 0x01, 0x00),
+		&DeclareRecipes{Recipes: []Recipe{{ID: "minecraft:a", Type: RecipeSmelting, Ingredients: []Ingredient{{{Present: true, ItemID: 1, Count: 1}}}, CookingTime: 200}}}: append(append(append(append([]byte{0x01, 0x0b}, "minecraft:a"...), 0x12), "minecraft:smelting"...),
+			0x00, 0x01, 0x01, 0x01, 0x01, 0x00, 0x00, 0, 0, 0, 0, 0xc8, 0x01),
+		&Advancements{Progress: []AdvancementProgress{{Key: "minecraft:a", Criteria: []CriterionProgress{{CriterionIdentifier: "minecraft:b", DateOfAchieving: &achieved}}}}}: append(append(append([]byte{0x00, 0x00, 0x00, 0x01, 0x0b}, "minecraft:a"...), append([]byte{0x01, 0x0b}, "minecraft:b"...)...),
+			0x01, 0, 0, 0x01, 0x68, 0x06, 0xb5, 0xbc, 0x00),
+		&LoginSuccess{UUID: "069a79f4-44e9-4726-a5be-fca90e38aaf5", Username: "Notch"}: append(append(append([]byte{0x24}, "069a79f4-44e9-4726-a5be-fca90e38aaf5"...), 0x05), "Notch"...),
+	})
+}
+
+// testPacketEncoding checks that each packet is encoded as the expected
+// bytes and decoded back to the same packet.
+func testPacketEncoding(t *testing.T, tests map[Handler][]byte) {
+	for p, expected := range tests {
+		b, err := p.Encode()
+		if err != nil {
+			t.Fatalf("Unexpected error encoding '%+v': '%v'", p, err)
+		}
+		if !bytes.Equal(b, expected) {
+			t.Fatalf("Expected '%+v' to be encoded as '%#02x' got '%#02x'", p, expected, b)
+		}
+
+		decoded := reflect.New(reflect.TypeOf(p).Elem()).Interface().(Handler)
+		if err := decoded.Decode(bufio.NewReader(bytes.NewReader(b))); err != nil {
+			t.Fatalf("Unexpected error decoding '%#02x': '%v'", b, err)
+		}
+		if !reflect.DeepEqual(decoded, p) {
+			t.Fatalf("Expected '%#02x' to be decoded as '%+v' got '%+v'", b, p, decoded)
+		}
+	}
+}
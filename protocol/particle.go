@@ -0,0 +1,86 @@
+package protocol
+
+import "fmt"
+
+// Particle IDs of protocol 404 (1.13.2) that carry extra data.
+const (
+	ParticleBlock       int32 = 3
+	ParticleDust        int32 = 11
+	ParticleFallingDust int32 = 20
+	ParticleItem        int32 = 27
+)
+
+// Particle is a particle type and its data. Only the fields used by the
+// particle type are encoded.
+// See https://wiki.vg/Protocol#Particle for more info.
+type Particle struct {
+	ID int32
+
+	// BlockState is used by ParticleBlock and ParticleFallingDust.
+	BlockState int32
+
+	// Red, Green, Blue and Scale are used by ParticleDust. The colors are in
+	// the range [0, 1] and Scale in the range [0.01, 4].
+	Red, Green, Blue, Scale float32
+
+	// Item is used by ParticleItem.
+	Item Slot
+}
+
+// MarshalProtocol implements the Marshaler interface. The particle ID is
+// encoded as a VarInt followed by its data.
+func (p Particle) MarshalProtocol(e *Encoder) error {
+	if err := e.WriteVarInt(p.ID); err != nil {
+		return err
+	}
+	return p.MarshalData(e)
+}
+
+// UnmarshalProtocol implements the Unmarshaler interface.
+func (p *Particle) UnmarshalProtocol(d *Decoder) error {
+	id, err := d.ReadVarInt()
+	if err != nil {
+		return err
+	}
+	if id < 0 {
+		return fmt.Errorf("protocol: invalid particle ID %d", id)
+	}
+	*p = Particle{ID: id}
+	return p.UnmarshalData(d)
+}
+
+// MarshalData encodes the data of the particle without its ID, as used by
+// the Particle packet which sends the ID separately.
+func (p Particle) MarshalData(e *Encoder) error {
+	switch p.ID {
+	case ParticleBlock, ParticleFallingDust:
+		return e.WriteVarInt(p.BlockState)
+	case ParticleDust:
+		for _, f := range []float32{p.Red, p.Green, p.Blue, p.Scale} {
+			if err := e.WriteFloat32(f); err != nil {
+				return err
+			}
+		}
+	case ParticleItem:
+		return p.Item.MarshalProtocol(e)
+	}
+	return nil
+}
+
+// UnmarshalData decodes the data of the particle with the ID already set.
+func (p *Particle) UnmarshalData(d *Decoder) error {
+	var err error
+	switch p.ID {
+	case ParticleBlock, ParticleFallingDust:
+		p.BlockState, err = d.ReadVarInt()
+	case ParticleDust:
+		for _, f := range []*float32{&p.Red, &p.Green, &p.Blue, &p.Scale} {
+			if *f, err = d.ReadFloat32(); err != nil {
+				return err
+			}
+		}
+	case ParticleItem:
+		err = p.Item.UnmarshalProtocol(d)
+	}
+	return err
+}
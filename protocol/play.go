@@ -1 +1,1955 @@
 package protocol
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/gofrs/uuid"
+)
+
+// Hand is the hand used by the player for an action.
+type Hand int32
+
+const (
+	MainHand Hand = iota
+	OffHand
+)
+
+// ClientStatusAction is the action of a ClientStatus packet.
+type ClientStatusAction int32
+
+const (
+	PerformRespawn ClientStatusAction = iota
+	RequestStats
+)
+
+// ChatMode is the chat visibility chosen in the client settings.
+type ChatMode int32
+
+const (
+	ChatEnabled ChatMode = iota
+	ChatCommandsOnly
+	ChatHidden
+)
+
+// MainHandSide is the hand the player chose as their main hand.
+type MainHandSide int32
+
+const (
+	MainHandLeft MainHandSide = iota
+	MainHandRight
+)
+
+// UseEntityType is the kind of interaction of a UseEntity packet.
+type UseEntityType int32
+
+const (
+	Interact UseEntityType = iota
+	Attack
+	InteractAt
+)
+
+// DiggingStatus is the action of a PlayerDigging packet.
+type DiggingStatus int32
+
+const (
+	StartedDigging DiggingStatus = iota
+	CancelledDigging
+	FinishedDigging
+	DropItemStack
+	DropItem
+	ShootArrowFinishEating
+	SwapItemInHand
+)
+
+// EntityActionID is the action of an EntityAction packet.
+type EntityActionID int32
+
+const (
+	StartSneaking EntityActionID = iota
+	StopSneaking
+	LeaveBed
+	StartSprinting
+	StopSprinting
+	StartJumpWithHorse
+	StopJumpWithHorse
+	OpenHorseInventory
+	StartFlyingWithElytra
+)
+
+// ResourcePackResult is the result of a ResourcePackStatus packet.
+type ResourcePackResult int32
+
+const (
+	ResourcePackLoaded ResourcePackResult = iota
+	ResourcePackDeclined
+	ResourcePackFailed
+	ResourcePackAccepted
+)
+
+// UseEntity is sent when the player attacks or right clicks an entity. The
+// target position is only sent for InteractAt and the hand is not sent for
+// Attack.
+type UseEntity struct {
+	Target                    int32
+	Type                      UseEntityType
+	TargetX, TargetY, TargetZ float32
+	Hand                      Hand
+}
+
+// MarshalProtocol implements the Marshaler interface.
+func (p *UseEntity) MarshalProtocol(e *Encoder) error {
+	if err := e.WriteVarInt(p.Target); err != nil {
+		return err
+	}
+	if err := e.WriteVarInt(int32(p.Type)); err != nil {
+		return err
+	}
+
+	switch p.Type {
+	case Interact:
+	case Attack:
+		return nil
+	case InteractAt:
+		for _, f := range []float32{p.TargetX, p.TargetY, p.TargetZ} {
+			if err := e.WriteFloat32(f); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("protocol: unknown use entity type %d", p.Type)
+	}
+	return e.WriteVarInt(int32(p.Hand))
+}
+
+// UnmarshalProtocol implements the Unmarshaler interface.
+func (p *UseEntity) UnmarshalProtocol(d *Decoder) error {
+	*p = UseEntity{}
+
+	var err error
+	if p.Target, err = d.ReadVarInt(); err != nil {
+		return err
+	}
+	t, err := d.ReadVarInt()
+	if err != nil {
+		return err
+	}
+	p.Type = UseEntityType(t)
+
+	switch p.Type {
+	case Interact:
+	case Attack:
+		return nil
+	case InteractAt:
+		for _, f := range []*float32{&p.TargetX, &p.TargetY, &p.TargetZ} {
+			if *f, err = d.ReadFloat32(); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("protocol: unknown use entity type %d", p.Type)
+	}
+
+	hand, err := d.ReadVarInt()
+	p.Hand = Hand(hand)
+	return err
+}
+
+// RecipeBookDataType is the kind of a RecipeBookData packet.
+type RecipeBookDataType int32
+
+const (
+	DisplayedRecipe RecipeBookDataType = iota
+	RecipeBookStates
+)
+
+// RecipeBookData is sent when the player views a recipe or changes the state
+// of their recipe book. RecipeID is only sent for DisplayedRecipe, the book
+// states only for RecipeBookStates.
+type RecipeBookData struct {
+	Type                       RecipeBookDataType
+	RecipeID                   Identifier
+	CraftingRecipeBookOpen     bool
+	CraftingRecipeFilterActive bool
+	SmeltingRecipeBookOpen     bool
+	SmeltingRecipeFilterActive bool
+}
+
+// MarshalProtocol implements the Marshaler interface.
+func (p *RecipeBookData) MarshalProtocol(e *Encoder) error {
+	if err := e.WriteVarInt(int32(p.Type)); err != nil {
+		return err
+	}
+
+	switch p.Type {
+	case DisplayedRecipe:
+		return p.RecipeID.MarshalProtocol(e)
+	case RecipeBookStates:
+		for _, b := range []bool{p.CraftingRecipeBookOpen, p.CraftingRecipeFilterActive, p.SmeltingRecipeBookOpen, p.SmeltingRecipeFilterActive} {
+			if err := e.WriteBool(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("protocol: unknown recipe book data type %d", p.Type)
+}
+
+// UnmarshalProtocol implements the Unmarshaler interface.
+func (p *RecipeBookData) UnmarshalProtocol(d *Decoder) error {
+	*p = RecipeBookData{}
+
+	t, err := d.ReadVarInt()
+	if err != nil {
+		return err
+	}
+	p.Type = RecipeBookDataType(t)
+
+	switch p.Type {
+	case DisplayedRecipe:
+		return p.RecipeID.UnmarshalProtocol(d)
+	case RecipeBookStates:
+		for _, b := range []*bool{&p.CraftingRecipeBookOpen, &p.CraftingRecipeFilterActive, &p.SmeltingRecipeBookOpen, &p.SmeltingRecipeFilterActive} {
+			if *b, err = d.ReadBool(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("protocol: unknown recipe book data type %d", p.Type)
+}
+
+// AdvancementTabAction is the action of an AdvancementTab packet.
+type AdvancementTabAction int32
+
+const (
+	OpenedTab AdvancementTabAction = iota
+	ClosedScreen
+)
+
+// AdvancementTab is sent when the player opens an advancement tab or closes
+// the advancement screen. TabID is only sent for OpenedTab.
+type AdvancementTab struct {
+	Action AdvancementTabAction
+	TabID  Identifier
+}
+
+// MarshalProtocol implements the Marshaler interface.
+func (p *AdvancementTab) MarshalProtocol(e *Encoder) error {
+	if err := e.WriteVarInt(int32(p.Action)); err != nil || p.Action != OpenedTab {
+		return err
+	}
+	return p.TabID.MarshalProtocol(e)
+}
+
+// UnmarshalProtocol implements the Unmarshaler interface.
+func (p *AdvancementTab) UnmarshalProtocol(d *Decoder) error {
+	*p = AdvancementTab{}
+
+	action, err := d.ReadVarInt()
+	if err != nil {
+		return err
+	}
+	p.Action = AdvancementTabAction(action)
+
+	if p.Action != OpenedTab {
+		return nil
+	}
+	return p.TabID.UnmarshalProtocol(d)
+}
+
+// BossBarAction is the action of a BossBar packet.
+type BossBarAction int32
+
+const (
+	BossBarAdd BossBarAction = iota
+	BossBarRemove
+	BossBarUpdateHealth
+	BossBarUpdateTitle
+	BossBarUpdateStyle
+	BossBarUpdateFlags
+)
+
+// BossBar adds, updates or removes a boss bar. Only the fields used by the
+// action are encoded.
+type BossBar struct {
+	UUID   uuid.UUID
+	Action BossBarAction
+
+	// Title is used by BossBarAdd and BossBarUpdateTitle.
+	Title Chat
+
+	// Health is used by BossBarAdd and BossBarUpdateHealth.
+	Health float32
+
+	// Color and Division are used by BossBarAdd and BossBarUpdateStyle.
+	Color    int32
+	Division int32
+
+	// Flags is used by BossBarAdd and BossBarUpdateFlags.
+	Flags uint8
+}
+
+// MarshalProtocol implements the Marshaler interface.
+func (p *BossBar) MarshalProtocol(e *Encoder) error {
+	if _, err := e.Write(p.UUID[:]); err != nil {
+		return err
+	}
+	if err := e.WriteVarInt(int32(p.Action)); err != nil {
+		return err
+	}
+
+	var err error
+	switch p.Action {
+	case BossBarAdd:
+		if err = p.Title.MarshalProtocol(e); err != nil {
+			return err
+		}
+		if err = e.WriteFloat32(p.Health); err != nil {
+			return err
+		}
+		if err = e.WriteVarInt(p.Color); err != nil {
+			return err
+		}
+		if err = e.WriteVarInt(p.Division); err != nil {
+			return err
+		}
+		err = e.WriteByte(p.Flags)
+	case BossBarRemove:
+	case BossBarUpdateHealth:
+		err = e.WriteFloat32(p.Health)
+	case BossBarUpdateTitle:
+		err = p.Title.MarshalProtocol(e)
+	case BossBarUpdateStyle:
+		if err = e.WriteVarInt(p.Color); err != nil {
+			return err
+		}
+		err = e.WriteVarInt(p.Division)
+	case BossBarUpdateFlags:
+		err = e.WriteByte(p.Flags)
+	default:
+		err = fmt.Errorf("protocol: unknown boss bar action %d", p.Action)
+	}
+	return err
+}
+
+// UnmarshalProtocol implements the Unmarshaler interface.
+func (p *BossBar) UnmarshalProtocol(d *Decoder) error {
+	*p = BossBar{}
+
+	if err := d.ReadFull(p.UUID[:]); err != nil {
+		return err
+	}
+	action, err := d.ReadVarInt()
+	if err != nil {
+		return err
+	}
+	p.Action = BossBarAction(action)
+
+	switch p.Action {
+	case BossBarAdd:
+		if err = p.Title.UnmarshalProtocol(d); err != nil {
+			return err
+		}
+		if p.Health, err = d.ReadFloat32(); err != nil {
+			return err
+		}
+		if p.Color, err = d.ReadVarInt(); err != nil {
+			return err
+		}
+		if p.Division, err = d.ReadVarInt(); err != nil {
+			return err
+		}
+		p.Flags, err = d.ReadByte()
+		err = noEOF(err)
+	case BossBarRemove:
+	case BossBarUpdateHealth:
+		p.Health, err = d.ReadFloat32()
+	case BossBarUpdateTitle:
+		err = p.Title.UnmarshalProtocol(d)
+	case BossBarUpdateStyle:
+		if p.Color, err = d.ReadVarInt(); err != nil {
+			return err
+		}
+		p.Division, err = d.ReadVarInt()
+	case BossBarUpdateFlags:
+		p.Flags, err = d.ReadByte()
+		err = noEOF(err)
+	default:
+		err = fmt.Errorf("protocol: unknown boss bar action %d", p.Action)
+	}
+	return err
+}
+
+// ChatPosition is where a chat message is displayed.
+type ChatPosition int8
+
+const (
+	ChatPositionChat ChatPosition = iota
+	ChatPositionSystem
+	ChatPositionGameInfo
+)
+
+// Command node types, stored in the lowest two bits of the node flags.
+const (
+	CommandNodeRoot     int8 = 0x00
+	CommandNodeLiteral  int8 = 0x01
+	CommandNodeArgument int8 = 0x02
+	CommandNodeTypeMask int8 = 0x03
+)
+
+// Command node flags.
+const (
+	CommandNodeExecutable  int8 = 0x04
+	CommandNodeRedirect    int8 = 0x08
+	CommandNodeSuggestions int8 = 0x10
+)
+
+// CommandNode is a node of the command graph sent by DeclareCommands.
+// See https://wiki.vg/Command_Data for more info.
+type CommandNode struct {
+	Flags    int8
+	Children []int32
+
+	// RedirectNode is used when Flags has CommandNodeRedirect.
+	RedirectNode int32
+
+	// Name is used by literal and argument nodes.
+	Name string
+
+	// Parser and Properties are used by argument nodes.
+	Parser     Identifier
+	Properties CommandProperties
+
+	// SuggestionsType is used when Flags has CommandNodeSuggestions.
+	SuggestionsType Identifier
+}
+
+// CommandProperties are the properties of an argument parser. Which fields
+// are used depends on the parser.
+type CommandProperties struct {
+	// Flags is used by brigadier:double, brigadier:float, brigadier:integer,
+	// minecraft:entity and minecraft:score_holder. For the number parsers
+	// 0x01 means Min is set and 0x02 means Max is set.
+	Flags int8
+
+	// Min and Max are used by brigadier:double, brigadier:float and
+	// brigadier:integer.
+	Min, Max float64
+
+	// StringType is used by brigadier:string.
+	StringType int32
+}
+
+// MarshalProtocol implements the Marshaler interface.
+func (n CommandNode) MarshalProtocol(e *Encoder) error {
+	if err := e.WriteByte(byte(n.Flags)); err != nil {
+		return err
+	}
+	if err := e.WriteVarInt(int32(len(n.Children))); err != nil {
+		return err
+	}
+	for _, c := range n.Children {
+		if err := e.WriteVarInt(c); err != nil {
+			return err
+		}
+	}
+
+	if n.Flags&CommandNodeRedirect != 0 {
+		if err := e.WriteVarInt(n.RedirectNode); err != nil {
+			return err
+		}
+	}
+
+	switch n.Flags & CommandNodeTypeMask {
+	case CommandNodeLiteral:
+		if err := e.WriteString(n.Name); err != nil {
+			return err
+		}
+	case CommandNodeArgument:
+		if err := e.WriteString(n.Name); err != nil {
+			return err
+		}
+		if err := n.Parser.MarshalProtocol(e); err != nil {
+			return err
+		}
+		if err := n.Properties.marshal(e, n.Parser); err != nil {
+			return err
+		}
+	}
+
+	if n.Flags&CommandNodeSuggestions != 0 {
+		return n.SuggestionsType.MarshalProtocol(e)
+	}
+	return nil
+}
+
+// UnmarshalProtocol implements the Unmarshaler interface.
+func (n *CommandNode) UnmarshalProtocol(d *Decoder) error {
+	*n = CommandNode{}
+
+	flags, err := d.ReadByte()
+	if err != nil {
+		return noEOF(err)
+	}
+	n.Flags = int8(flags)
+
+	count, err := d.ReadVarInt()
+	if err != nil {
+		return err
+	}
+	if count < 0 {
+		return fmt.Errorf("protocol: negative array length %d", count)
+	}
+	for i := int32(0); i < count; i++ {
+		c, err := d.ReadVarInt()
+		if err != nil {
+			return err
+		}
+		n.Children = append(n.Children, c)
+	}
+
+	if n.Flags&CommandNodeRedirect != 0 {
+		if n.RedirectNode, err = d.ReadVarInt(); err != nil {
+			return err
+		}
+	}
+
+	switch n.Flags & CommandNodeTypeMask {
+	case CommandNodeLiteral:
+		if n.Name, err = d.ReadString(MaxStringLength); err != nil {
+			return err
+		}
+	case CommandNodeArgument:
+		if n.Name, err = d.ReadString(MaxStringLength); err != nil {
+			return err
+		}
+		if err := n.Parser.UnmarshalProtocol(d); err != nil {
+			return err
+		}
+		if err := n.Properties.unmarshal(d, n.Parser); err != nil {
+			return err
+		}
+	}
+
+	if n.Flags&CommandNodeSuggestions != 0 {
+		return n.SuggestionsType.UnmarshalProtocol(d)
+	}
+	return nil
+}
+
+func (p CommandProperties) marshal(e *Encoder, parser Identifier) error {
+	switch parser {
+	case "brigadier:double", "brigadier:float", "brigadier:integer":
+		if err := e.WriteByte(byte(p.Flags)); err != nil {
+			return err
+		}
+		for i, v := range []float64{p.Min, p.Max} {
+			if p.Flags&(1<<uint(i)) == 0 {
+				continue
+			}
+
+			var err error
+			switch parser {
+			case "brigadier:double":
+				err = e.WriteFloat64(v)
+			case "brigadier:float":
+				err = e.WriteFloat32(float32(v))
+			default:
+				err = e.WriteUint32(uint32(int32(v)))
+			}
+			if err != nil {
+				return err
+			}
+		}
+	case "brigadier:string":
+		return e.WriteVarInt(p.StringType)
+	case "minecraft:entity", "minecraft:score_holder":
+		return e.WriteByte(byte(p.Flags))
+	}
+	return nil
+}
+
+func (p *CommandProperties) unmarshal(d *Decoder, parser Identifier) error {
+	switch parser {
+	case "brigadier:double", "brigadier:float", "brigadier:integer":
+		flags, err := d.ReadByte()
+		if err != nil {
+			return noEOF(err)
+		}
+		p.Flags = int8(flags)
+
+		for i, v := range []*float64{&p.Min, &p.Max} {
+			if p.Flags&(1<<uint(i)) == 0 {
+				continue
+			}
+
+			switch parser {
+			case "brigadier:double":
+				*v, err = d.ReadFloat64()
+			case "brigadier:float":
+				var f float32
+				f, err = d.ReadFloat32()
+				*v = float64(f)
+			default:
+				var n uint32
+				n, err = d.ReadUint32()
+				*v = float64(int32(n))
+			}
+			if err != nil {
+				return err
+			}
+		}
+	case "brigadier:string":
+		var err error
+		p.StringType, err = d.ReadVarInt()
+		return err
+	case "minecraft:entity", "minecraft:score_holder":
+		flags, err := d.ReadByte()
+		p.Flags = int8(flags)
+		return noEOF(err)
+	}
+	return nil
+}
+
+// OpenWindow opens a window on the client. EntityID is only sent when
+// WindowType is "EntityHorse".
+type OpenWindow struct {
+	WindowID      uint8
+	WindowType    string
+	WindowTitle   Chat
+	NumberOfSlots uint8
+	EntityID      int32
+}
+
+// MarshalProtocol implements the Marshaler interface.
+func (p *OpenWindow) MarshalProtocol(e *Encoder) error {
+	if err := e.WriteByte(p.WindowID); err != nil {
+		return err
+	}
+	if n := utf8.RuneCountInString(p.WindowType); n > 32 {
+		return fmt.Errorf("protocol: String length %d exceeds maximum of %d", n, 32)
+	}
+	if err := e.WriteString(p.WindowType); err != nil {
+		return err
+	}
+	if err := p.WindowTitle.MarshalProtocol(e); err != nil {
+		return err
+	}
+	if err := e.WriteByte(p.NumberOfSlots); err != nil {
+		return err
+	}
+
+	if p.WindowType != "EntityHorse" {
+		return nil
+	}
+	return e.WriteUint32(uint32(p.EntityID))
+}
+
+// UnmarshalProtocol implements the Unmarshaler interface.
+func (p *OpenWindow) UnmarshalProtocol(d *Decoder) error {
+	*p = OpenWindow{}
+
+	var err error
+	if p.WindowID, err = d.ReadByte(); err != nil {
+		return noEOF(err)
+	}
+	if p.WindowType, err = d.ReadString(32); err != nil {
+		return err
+	}
+	if err = p.WindowTitle.UnmarshalProtocol(d); err != nil {
+		return err
+	}
+	if p.NumberOfSlots, err = d.ReadByte(); err != nil {
+		return noEOF(err)
+	}
+
+	if p.WindowType != "EntityHorse" {
+		return nil
+	}
+	id, err := d.ReadUint32()
+	p.EntityID = int32(id)
+	return err
+}
+
+// ClientboundParticle spawns particles. The ID of Particle is sent as an Int
+// before the other fields and its data at the end.
+type ClientboundParticle struct {
+	Particle                  Particle
+	LongDistance              bool
+	X, Y, Z                   float32
+	OffsetX, OffsetY, OffsetZ float32
+	ParticleData              float32
+	ParticleCount             int32
+}
+
+// MarshalProtocol implements the Marshaler interface.
+func (p *ClientboundParticle) MarshalProtocol(e *Encoder) error {
+	if err := e.WriteUint32(uint32(p.Particle.ID)); err != nil {
+		return err
+	}
+	if err := e.WriteBool(p.LongDistance); err != nil {
+		return err
+	}
+	for _, f := range []float32{p.X, p.Y, p.Z, p.OffsetX, p.OffsetY, p.OffsetZ, p.ParticleData} {
+		if err := e.WriteFloat32(f); err != nil {
+			return err
+		}
+	}
+	if err := e.WriteUint32(uint32(p.ParticleCount)); err != nil {
+		return err
+	}
+	return p.Particle.MarshalData(e)
+}
+
+// UnmarshalProtocol implements the Unmarshaler interface.
+func (p *ClientboundParticle) UnmarshalProtocol(d *Decoder) error {
+	*p = ClientboundParticle{}
+
+	id, err := d.ReadUint32()
+	if err != nil {
+		return err
+	}
+	if int32(id) < 0 {
+		return fmt.Errorf("protocol: invalid particle ID %d", int32(id))
+	}
+	p.Particle.ID = int32(id)
+
+	if p.LongDistance, err = d.ReadBool(); err != nil {
+		return err
+	}
+	for _, f := range []*float32{&p.X, &p.Y, &p.Z, &p.OffsetX, &p.OffsetY, &p.OffsetZ, &p.ParticleData} {
+		if *f, err = d.ReadFloat32(); err != nil {
+			return err
+		}
+	}
+	count, err := d.ReadUint32()
+	if err != nil {
+		return err
+	}
+	p.ParticleCount = int32(count)
+
+	return p.Particle.UnmarshalData(d)
+}
+
+// MapIcon is an icon shown on a map.
+type MapIcon struct {
+	Type        int32 `mc:"varint"`
+	X, Z        int8
+	Direction   int8
+	DisplayName *Chat `mc:",optional"`
+}
+
+// MapData updates a map item. Rows, X, Z and Data are only sent when Columns
+// is not 0.
+type MapData struct {
+	ItemDamage       int32
+	Scale            int8
+	TrackingPosition bool
+	Icons            []MapIcon
+	Columns          uint8
+	Rows             int8
+	X, Z             int8
+	Data             []byte
+}
+
+// MarshalProtocol implements the Marshaler interface.
+func (p *MapData) MarshalProtocol(e *Encoder) error {
+	if err := e.WriteVarInt(p.ItemDamage); err != nil {
+		return err
+	}
+	if err := e.WriteByte(byte(p.Scale)); err != nil {
+		return err
+	}
+	if err := e.WriteBool(p.TrackingPosition); err != nil {
+		return err
+	}
+	if err := e.Encode(p.Icons); err != nil {
+		return err
+	}
+	if err := e.WriteByte(p.Columns); err != nil || p.Columns == 0 {
+		return err
+	}
+
+	for _, b := range []int8{p.Rows, p.X, p.Z} {
+		if err := e.WriteByte(byte(b)); err != nil {
+			return err
+		}
+	}
+	return e.Encode(p.Data)
+}
+
+// UnmarshalProtocol implements the Unmarshaler interface.
+func (p *MapData) UnmarshalProtocol(d *Decoder) error {
+	*p = MapData{}
+
+	var err error
+	if p.ItemDamage, err = d.ReadVarInt(); err != nil {
+		return err
+	}
+	scale, err := d.ReadByte()
+	if err != nil {
+		return noEOF(err)
+	}
+	p.Scale = int8(scale)
+	if p.TrackingPosition, err = d.ReadBool(); err != nil {
+		return err
+	}
+	if err := d.Decode(&p.Icons); err != nil {
+		return err
+	}
+	if p.Columns, err = d.ReadByte(); err != nil || p.Columns == 0 {
+		return noEOF(err)
+	}
+
+	for _, b := range []*int8{&p.Rows, &p.X, &p.Z} {
+		v, err := d.ReadByte()
+		if err != nil {
+			return noEOF(err)
+		}
+		*b = int8(v)
+	}
+	return d.Decode(&p.Data)
+}
+
+// CombatEventType is the event of a CombatEvent packet.
+type CombatEventType int32
+
+const (
+	EnterCombat CombatEventType = iota
+	EndCombat
+	EntityDead
+)
+
+// CombatEvent is used by the client for the twitch integration. Only the
+// fields used by the event are encoded.
+type CombatEvent struct {
+	Event CombatEventType
+
+	// Duration is used by EndCombat.
+	Duration int32
+
+	// EntityID is used by EndCombat and EntityDead.
+	EntityID int32
+
+	// PlayerID and Message are used by EntityDead.
+	PlayerID int32
+	Message  Chat
+}
+
+// MarshalProtocol implements the Marshaler interface.
+func (p *CombatEvent) MarshalProtocol(e *Encoder) error {
+	if err := e.WriteVarInt(int32(p.Event)); err != nil {
+		return err
+	}
+
+	switch p.Event {
+	case EnterCombat:
+		return nil
+	case EndCombat:
+		if err := e.WriteVarInt(p.Duration); err != nil {
+			return err
+		}
+		return e.WriteUint32(uint32(p.EntityID))
+	case EntityDead:
+		if err := e.WriteVarInt(p.PlayerID); err != nil {
+			return err
+		}
+		if err := e.WriteUint32(uint32(p.EntityID)); err != nil {
+			return err
+		}
+		return p.Message.MarshalProtocol(e)
+	}
+	return fmt.Errorf("protocol: unknown combat event %d", p.Event)
+}
+
+// UnmarshalProtocol implements the Unmarshaler interface.
+func (p *CombatEvent) UnmarshalProtocol(d *Decoder) error {
+	*p = CombatEvent{}
+
+	event, err := d.ReadVarInt()
+	if err != nil {
+		return err
+	}
+	p.Event = CombatEventType(event)
+
+	switch p.Event {
+	case EnterCombat:
+		return nil
+	case EndCombat:
+		if p.Duration, err = d.ReadVarInt(); err != nil {
+			return err
+		}
+		id, err := d.ReadUint32()
+		p.EntityID = int32(id)
+		return err
+	case EntityDead:
+		if p.PlayerID, err = d.ReadVarInt(); err != nil {
+			return err
+		}
+		id, err := d.ReadUint32()
+		if err != nil {
+			return err
+		}
+		p.EntityID = int32(id)
+		return p.Message.UnmarshalProtocol(d)
+	}
+	return fmt.Errorf("protocol: unknown combat event %d", p.Event)
+}
+
+// PlayerInfoAction is the action of a PlayerInfo packet.
+type PlayerInfoAction int32
+
+const (
+	AddPlayer PlayerInfoAction = iota
+	UpdateGamemode
+	UpdateLatency
+	UpdateDisplayName
+	RemovePlayer
+)
+
+// PlayerProperty is a property of a player's profile, such as their skin.
+type PlayerProperty struct {
+	Name      string
+	Value     string
+	Signature *string `mc:",optional"`
+}
+
+// PlayerInfoEntry is a player of a PlayerInfo packet. Only the fields used
+// by the action are encoded.
+type PlayerInfoEntry struct {
+	UUID uuid.UUID
+
+	// Name and Properties are used by AddPlayer.
+	Name       string
+	Properties []PlayerProperty
+
+	// Gamemode is used by AddPlayer and UpdateGamemode.
+	Gamemode int32
+
+	// Ping is used by AddPlayer and UpdateLatency.
+	Ping int32
+
+	// DisplayName is used by AddPlayer and UpdateDisplayName.
+	DisplayName *Chat
+}
+
+// PlayerInfo updates the player list.
+type PlayerInfo struct {
+	Action  PlayerInfoAction
+	Players []PlayerInfoEntry
+}
+
+// MarshalProtocol implements the Marshaler interface.
+func (p *PlayerInfo) MarshalProtocol(e *Encoder) error {
+	if p.Action < AddPlayer || p.Action > RemovePlayer {
+		return fmt.Errorf("protocol: unknown player info action %d", p.Action)
+	}
+	if err := e.WriteVarInt(int32(p.Action)); err != nil {
+		return err
+	}
+	if err := e.WriteVarInt(int32(len(p.Players))); err != nil {
+		return err
+	}
+
+	for _, player := range p.Players {
+		if _, err := e.Write(player.UUID[:]); err != nil {
+			return err
+		}
+
+		if p.Action == AddPlayer {
+			if n := utf8.RuneCountInString(player.Name); n > 16 {
+				return fmt.Errorf("protocol: String length %d exceeds maximum of %d", n, 16)
+			}
+			if err := e.WriteString(player.Name); err != nil {
+				return err
+			}
+			if err := e.Encode(player.Properties); err != nil {
+				return err
+			}
+		}
+		if p.Action == AddPlayer || p.Action == UpdateGamemode {
+			if err := e.WriteVarInt(player.Gamemode); err != nil {
+				return err
+			}
+		}
+		if p.Action == AddPlayer || p.Action == UpdateLatency {
+			if err := e.WriteVarInt(player.Ping); err != nil {
+				return err
+			}
+		}
+		if p.Action == AddPlayer || p.Action == UpdateDisplayName {
+			if err := e.WriteBool(player.DisplayName != nil); err != nil {
+				return err
+			}
+			if player.DisplayName != nil {
+				if err := player.DisplayName.MarshalProtocol(e); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// UnmarshalProtocol implements the Unmarshaler interface.
+func (p *PlayerInfo) UnmarshalProtocol(d *Decoder) error {
+	*p = PlayerInfo{}
+
+	action, err := d.ReadVarInt()
+	if err != nil {
+		return err
+	}
+	p.Action = PlayerInfoAction(action)
+	if p.Action < AddPlayer || p.Action > RemovePlayer {
+		return fmt.Errorf("protocol: unknown player info action %d", p.Action)
+	}
+
+	count, err := d.ReadVarInt()
+	if err != nil {
+		return err
+	}
+	if count < 0 {
+		return fmt.Errorf("protocol: negative array length %d", count)
+	}
+
+	for i := int32(0); i < count; i++ {
+		var player PlayerInfoEntry
+		if err := d.ReadFull(player.UUID[:]); err != nil {
+			return err
+		}
+
+		if p.Action == AddPlayer {
+			if player.Name, err = d.ReadString(16); err != nil {
+				return err
+			}
+			if err := d.Decode(&player.Properties); err != nil {
+				return err
+			}
+		}
+		if p.Action == AddPlayer || p.Action == UpdateGamemode {
+			if player.Gamemode, err = d.ReadVarInt(); err != nil {
+				return err
+			}
+		}
+		if p.Action == AddPlayer || p.Action == UpdateLatency {
+			if player.Ping, err = d.ReadVarInt(); err != nil {
+				return err
+			}
+		}
+		if p.Action == AddPlayer || p.Action == UpdateDisplayName {
+			hasDisplayName, err := d.ReadBool()
+			if err != nil {
+				return err
+			}
+			if hasDisplayName {
+				player.DisplayName = &Chat{}
+				if err := player.DisplayName.UnmarshalProtocol(d); err != nil {
+					return err
+				}
+			}
+		}
+
+		p.Players = append(p.Players, player)
+	}
+	return nil
+}
+
+// FacePlayerAnchor is the part of an entity used to aim at a target.
+type FacePlayerAnchor int32
+
+const (
+	AnchorFeet FacePlayerAnchor = iota
+	AnchorEyes
+)
+
+// FacePlayer rotates the player to look at a point or an entity. EntityID
+// and EntityFeetEyes are only sent when IsEntity is true.
+type FacePlayer struct {
+	FeetEyes                  FacePlayerAnchor
+	TargetX, TargetY, TargetZ float64
+	IsEntity                  bool
+	EntityID                  int32
+	EntityFeetEyes            FacePlayerAnchor
+}
+
+// MarshalProtocol implements the Marshaler interface.
+func (p *FacePlayer) MarshalProtocol(e *Encoder) error {
+	if err := e.WriteVarInt(int32(p.FeetEyes)); err != nil {
+		return err
+	}
+	for _, f := range []float64{p.TargetX, p.TargetY, p.TargetZ} {
+		if err := e.WriteFloat64(f); err != nil {
+			return err
+		}
+	}
+	if err := e.WriteBool(p.IsEntity); err != nil || !p.IsEntity {
+		return err
+	}
+
+	if err := e.WriteVarInt(p.EntityID); err != nil {
+		return err
+	}
+	return e.WriteVarInt(int32(p.EntityFeetEyes))
+}
+
+// UnmarshalProtocol implements the Unmarshaler interface.
+func (p *FacePlayer) UnmarshalProtocol(d *Decoder) error {
+	*p = FacePlayer{}
+
+	feetEyes, err := d.ReadVarInt()
+	if err != nil {
+		return err
+	}
+	p.FeetEyes = FacePlayerAnchor(feetEyes)
+	for _, f := range []*float64{&p.TargetX, &p.TargetY, &p.TargetZ} {
+		if *f, err = d.ReadFloat64(); err != nil {
+			return err
+		}
+	}
+	if p.IsEntity, err = d.ReadBool(); err != nil || !p.IsEntity {
+		return err
+	}
+
+	if p.EntityID, err = d.ReadVarInt(); err != nil {
+		return err
+	}
+	feetEyes, err = d.ReadVarInt()
+	p.EntityFeetEyes = FacePlayerAnchor(feetEyes)
+	return err
+}
+
+// Flags of a ClientboundPlayerPositionAndLook, set when the field is
+// relative to the current position instead of absolute.
+const (
+	RelativeX     int8 = 0x01
+	RelativeY     int8 = 0x02
+	RelativeZ     int8 = 0x04
+	RelativePitch int8 = 0x08
+	RelativeYaw   int8 = 0x10
+)
+
+// UnlockRecipesAction is the action of an UnlockRecipes packet.
+type UnlockRecipesAction int32
+
+const (
+	InitRecipes UnlockRecipesAction = iota
+	AddRecipes
+	RemoveRecipes
+)
+
+// UnlockRecipes updates the recipes unlocked by the player. InitRecipeIDs is
+// only sent for InitRecipes and holds the recipes already unlocked, while
+// RecipeIDs holds the ones to show a notification for.
+type UnlockRecipes struct {
+	Action                         UnlockRecipesAction
+	CraftingRecipeBookOpen         bool
+	CraftingRecipeBookFilterActive bool
+	SmeltingRecipeBookOpen         bool
+	SmeltingRecipeBookFilterActive bool
+	RecipeIDs                      []Identifier
+	InitRecipeIDs                  []Identifier
+}
+
+// MarshalProtocol implements the Marshaler interface.
+func (p *UnlockRecipes) MarshalProtocol(e *Encoder) error {
+	if err := e.WriteVarInt(int32(p.Action)); err != nil {
+		return err
+	}
+	for _, b := range []bool{p.CraftingRecipeBookOpen, p.CraftingRecipeBookFilterActive, p.SmeltingRecipeBookOpen, p.SmeltingRecipeBookFilterActive} {
+		if err := e.WriteBool(b); err != nil {
+			return err
+		}
+	}
+	if err := e.Encode(p.RecipeIDs); err != nil || p.Action != InitRecipes {
+		return err
+	}
+	return e.Encode(p.InitRecipeIDs)
+}
+
+// UnmarshalProtocol implements the Unmarshaler interface.
+func (p *UnlockRecipes) UnmarshalProtocol(d *Decoder) error {
+	*p = UnlockRecipes{}
+
+	action, err := d.ReadVarInt()
+	if err != nil {
+		return err
+	}
+	p.Action = UnlockRecipesAction(action)
+	for _, b := range []*bool{&p.CraftingRecipeBookOpen, &p.CraftingRecipeBookFilterActive, &p.SmeltingRecipeBookOpen, &p.SmeltingRecipeBookFilterActive} {
+		if *b, err = d.ReadBool(); err != nil {
+			return err
+		}
+	}
+	if err := d.Decode(&p.RecipeIDs); err != nil || p.Action != InitRecipes {
+		return err
+	}
+	return d.Decode(&p.InitRecipeIDs)
+}
+
+// WorldBorderAction is the action of a WorldBorder packet.
+type WorldBorderAction int32
+
+const (
+	WorldBorderSetSize WorldBorderAction = iota
+	WorldBorderLerpSize
+	WorldBorderSetCenter
+	WorldBorderInitialize
+	WorldBorderSetWarningTime
+	WorldBorderSetWarningBlocks
+)
+
+// WorldBorder updates the world border. Only the fields used by the action
+// are encoded.
+type WorldBorder struct {
+	Action WorldBorderAction
+
+	// Diameter is used by WorldBorderSetSize.
+	Diameter float64
+
+	// OldDiameter, NewDiameter and Speed are used by WorldBorderLerpSize and
+	// WorldBorderInitialize.
+	OldDiameter float64
+	NewDiameter float64
+	Speed       int64
+
+	// X and Z are used by WorldBorderSetCenter and WorldBorderInitialize.
+	X, Z float64
+
+	// PortalTeleportBoundary is used by WorldBorderInitialize.
+	PortalTeleportBoundary int32
+
+	// WarningTime is used by WorldBorderSetWarningTime and
+	// WorldBorderInitialize.
+	WarningTime int32
+
+	// WarningBlocks is used by WorldBorderSetWarningBlocks and
+	// WorldBorderInitialize.
+	WarningBlocks int32
+}
+
+// MarshalProtocol implements the Marshaler interface.
+func (p *WorldBorder) MarshalProtocol(e *Encoder) error {
+	if err := e.WriteVarInt(int32(p.Action)); err != nil {
+		return err
+	}
+
+	var err error
+	switch p.Action {
+	case WorldBorderSetSize:
+		err = e.WriteFloat64(p.Diameter)
+	case WorldBorderLerpSize:
+		err = p.marshalLerp(e)
+	case WorldBorderSetCenter:
+		if err = e.WriteFloat64(p.X); err != nil {
+			return err
+		}
+		err = e.WriteFloat64(p.Z)
+	case WorldBorderInitialize:
+		if err = e.WriteFloat64(p.X); err != nil {
+			return err
+		}
+		if err = e.WriteFloat64(p.Z); err != nil {
+			return err
+		}
+		if err = p.marshalLerp(e); err != nil {
+			return err
+		}
+		for _, v := range []int32{p.PortalTeleportBoundary, p.WarningTime, p.WarningBlocks} {
+			if err = e.WriteVarInt(v); err != nil {
+				return err
+			}
+		}
+	case WorldBorderSetWarningTime:
+		err = e.WriteVarInt(p.WarningTime)
+	case WorldBorderSetWarningBlocks:
+		err = e.WriteVarInt(p.WarningBlocks)
+	default:
+		err = fmt.Errorf("protocol: unknown world border action %d", p.Action)
+	}
+	return err
+}
+
+func (p *WorldBorder) marshalLerp(e *Encoder) error {
+	if err := e.WriteFloat64(p.OldDiameter); err != nil {
+		return err
+	}
+	if err := e.WriteFloat64(p.NewDiameter); err != nil {
+		return err
+	}
+	return e.WriteVarLong(p.Speed)
+}
+
+// UnmarshalProtocol implements the Unmarshaler interface.
+func (p *WorldBorder) UnmarshalProtocol(d *Decoder) error {
+	*p = WorldBorder{}
+
+	action, err := d.ReadVarInt()
+	if err != nil {
+		return err
+	}
+	p.Action = WorldBorderAction(action)
+
+	switch p.Action {
+	case WorldBorderSetSize:
+		p.Diameter, err = d.ReadFloat64()
+	case WorldBorderLerpSize:
+		err = p.unmarshalLerp(d)
+	case WorldBorderSetCenter:
+		if p.X, err = d.ReadFloat64(); err != nil {
+			return err
+		}
+		p.Z, err = d.ReadFloat64()
+	case WorldBorderInitialize:
+		if p.X, err = d.ReadFloat64(); err != nil {
+			return err
+		}
+		if p.Z, err = d.ReadFloat64(); err != nil {
+			return err
+		}
+		if err = p.unmarshalLerp(d); err != nil {
+			return err
+		}
+		for _, v := range []*int32{&p.PortalTeleportBoundary, &p.WarningTime, &p.WarningBlocks} {
+			if *v, err = d.ReadVarInt(); err != nil {
+				return err
+			}
+		}
+	case WorldBorderSetWarningTime:
+		p.WarningTime, err = d.ReadVarInt()
+	case WorldBorderSetWarningBlocks:
+		p.WarningBlocks, err = d.ReadVarInt()
+	default:
+		err = fmt.Errorf("protocol: unknown world border action %d", p.Action)
+	}
+	return err
+}
+
+func (p *WorldBorder) unmarshalLerp(d *Decoder) error {
+	var err error
+	if p.OldDiameter, err = d.ReadFloat64(); err != nil {
+		return err
+	}
+	if p.NewDiameter, err = d.ReadFloat64(); err != nil {
+		return err
+	}
+	p.Speed, err = d.ReadVarLong()
+	return err
+}
+
+// EquipmentSlot is the slot of an EntityEquipment packet.
+type EquipmentSlot int32
+
+const (
+	EquipmentMainHand EquipmentSlot = iota
+	EquipmentOffHand
+	EquipmentBoots
+	EquipmentLeggings
+	EquipmentChestplate
+	EquipmentHelmet
+)
+
+// Modes of the ScoreboardObjective and Teams packets.
+const (
+	ScoreboardCreate int8 = 0
+	ScoreboardRemove int8 = 1
+	ScoreboardUpdate int8 = 2
+)
+
+// ScoreboardObjective creates, removes or updates a scoreboard objective.
+// ObjectiveValue and Type are not sent when removing it. Type is integer (0)
+// or hearts (1).
+type ScoreboardObjective struct {
+	ObjectiveName  string
+	Mode           int8
+	ObjectiveValue Chat
+	Type           int32
+}
+
+// MarshalProtocol implements the Marshaler interface.
+func (p *ScoreboardObjective) MarshalProtocol(e *Encoder) error {
+	if n := utf8.RuneCountInString(p.ObjectiveName); n > 16 {
+		return fmt.Errorf("protocol: String length %d exceeds maximum of %d", n, 16)
+	}
+	if err := e.WriteString(p.ObjectiveName); err != nil {
+		return err
+	}
+	if err := e.WriteByte(byte(p.Mode)); err != nil || p.Mode == ScoreboardRemove {
+		return err
+	}
+
+	if err := p.ObjectiveValue.MarshalProtocol(e); err != nil {
+		return err
+	}
+	return e.WriteVarInt(p.Type)
+}
+
+// UnmarshalProtocol implements the Unmarshaler interface.
+func (p *ScoreboardObjective) UnmarshalProtocol(d *Decoder) error {
+	*p = ScoreboardObjective{}
+
+	var err error
+	if p.ObjectiveName, err = d.ReadString(16); err != nil {
+		return err
+	}
+	mode, err := d.ReadByte()
+	if err != nil {
+		return noEOF(err)
+	}
+	p.Mode = int8(mode)
+	if p.Mode == ScoreboardRemove {
+		return nil
+	}
+
+	if err := p.ObjectiveValue.UnmarshalProtocol(d); err != nil {
+		return err
+	}
+	p.Type, err = d.ReadVarInt()
+	return err
+}
+
+// Modes of the Teams packet besides ScoreboardCreate, ScoreboardRemove and
+// ScoreboardUpdate.
+const (
+	TeamAddEntities    int8 = 3
+	TeamRemoveEntities int8 = 4
+)
+
+// Teams creates, removes or updates a team. Only the fields used by the mode
+// are encoded: the team info for ScoreboardCreate and ScoreboardUpdate and
+// the entities for ScoreboardCreate, TeamAddEntities and TeamRemoveEntities.
+type Teams struct {
+	TeamName string
+	Mode     int8
+
+	TeamDisplayName   Chat
+	FriendlyFlags     int8
+	NameTagVisibility string
+	CollisionRule     string
+	TeamColor         int32
+	TeamPrefix        Chat
+	TeamSuffix        Chat
+
+	// Entities holds player names and entity UUIDs.
+	Entities []string
+}
+
+// teamInfo is the team info of a Teams packet.
+type teamInfo struct {
+	TeamDisplayName   Chat
+	FriendlyFlags     int8
+	NameTagVisibility string `mc:"string,max=32"`
+	CollisionRule     string `mc:"string,max=32"`
+	TeamColor         int32  `mc:"varint"`
+	TeamPrefix        Chat
+	TeamSuffix        Chat
+}
+
+// MarshalProtocol implements the Marshaler interface.
+func (p *Teams) MarshalProtocol(e *Encoder) error {
+	if n := utf8.RuneCountInString(p.TeamName); n > 16 {
+		return fmt.Errorf("protocol: String length %d exceeds maximum of %d", n, 16)
+	}
+	if err := e.WriteString(p.TeamName); err != nil {
+		return err
+	}
+	if err := e.WriteByte(byte(p.Mode)); err != nil {
+		return err
+	}
+
+	if p.Mode == ScoreboardCreate || p.Mode == ScoreboardUpdate {
+		info := teamInfo{
+			TeamDisplayName:   p.TeamDisplayName,
+			FriendlyFlags:     p.FriendlyFlags,
+			NameTagVisibility: p.NameTagVisibility,
+			CollisionRule:     p.CollisionRule,
+			TeamColor:         p.TeamColor,
+			TeamPrefix:        p.TeamPrefix,
+			TeamSuffix:        p.TeamSuffix,
+		}
+		if err := e.Encode(&info); err != nil {
+			return err
+		}
+	}
+
+	switch p.Mode {
+	case ScoreboardCreate, TeamAddEntities, TeamRemoveEntities:
+		return e.Encode(&entityNames{Entities: p.Entities})
+	case ScoreboardRemove, ScoreboardUpdate:
+		return nil
+	}
+	return fmt.Errorf("protocol: unknown team mode %d", p.Mode)
+}
+
+// UnmarshalProtocol implements the Unmarshaler interface.
+func (p *Teams) UnmarshalProtocol(d *Decoder) error {
+	*p = Teams{}
+
+	var err error
+	if p.TeamName, err = d.ReadString(16); err != nil {
+		return err
+	}
+	mode, err := d.ReadByte()
+	if err != nil {
+		return noEOF(err)
+	}
+	p.Mode = int8(mode)
+
+	if p.Mode == ScoreboardCreate || p.Mode == ScoreboardUpdate {
+		var info teamInfo
+		if err := d.Decode(&info); err != nil {
+			return err
+		}
+		p.TeamDisplayName = info.TeamDisplayName
+		p.FriendlyFlags = info.FriendlyFlags
+		p.NameTagVisibility = info.NameTagVisibility
+		p.CollisionRule = info.CollisionRule
+		p.TeamColor = info.TeamColor
+		p.TeamPrefix = info.TeamPrefix
+		p.TeamSuffix = info.TeamSuffix
+	}
+
+	switch p.Mode {
+	case ScoreboardCreate, TeamAddEntities, TeamRemoveEntities:
+		var names entityNames
+		err := d.Decode(&names)
+		p.Entities = names.Entities
+		return err
+	case ScoreboardRemove, ScoreboardUpdate:
+		return nil
+	}
+	return fmt.Errorf("protocol: unknown team mode %d", p.Mode)
+}
+
+// entityNames is the entity list of a Teams packet.
+type entityNames struct {
+	Entities []string `mc:"string,max=40"`
+}
+
+// Actions of the UpdateScore packet.
+const (
+	ScoreUpdate int8 = 0
+	ScoreRemove int8 = 1
+)
+
+// UpdateScore updates or removes the score of an entity. Value is not sent
+// when removing it.
+type UpdateScore struct {
+	EntityName    string
+	Action        int8
+	ObjectiveName string
+	Value         int32
+}
+
+// MarshalProtocol implements the Marshaler interface.
+func (p *UpdateScore) MarshalProtocol(e *Encoder) error {
+	if n := utf8.RuneCountInString(p.EntityName); n > 40 {
+		return fmt.Errorf("protocol: String length %d exceeds maximum of %d", n, 40)
+	}
+	if n := utf8.RuneCountInString(p.ObjectiveName); n > 16 {
+		return fmt.Errorf("protocol: String length %d exceeds maximum of %d", n, 16)
+	}
+	if err := e.WriteString(p.EntityName); err != nil {
+		return err
+	}
+	if err := e.WriteByte(byte(p.Action)); err != nil {
+		return err
+	}
+	if err := e.WriteString(p.ObjectiveName); err != nil || p.Action == ScoreRemove {
+		return err
+	}
+	return e.WriteVarInt(p.Value)
+}
+
+// UnmarshalProtocol implements the Unmarshaler interface.
+func (p *UpdateScore) UnmarshalProtocol(d *Decoder) error {
+	*p = UpdateScore{}
+
+	var err error
+	if p.EntityName, err = d.ReadString(40); err != nil {
+		return err
+	}
+	action, err := d.ReadByte()
+	if err != nil {
+		return noEOF(err)
+	}
+	p.Action = int8(action)
+	if p.ObjectiveName, err = d.ReadString(16); err != nil || p.Action == ScoreRemove {
+		return err
+	}
+	p.Value, err = d.ReadVarInt()
+	return err
+}
+
+// TitleAction is the action of a Title packet.
+type TitleAction int32
+
+const (
+	SetTitle TitleAction = iota
+	SetSubtitle
+	SetActionBar
+	SetTimesAndDisplay
+	HideTitle
+	ResetTitle
+)
+
+// Title shows or hides a title. Text is used by SetTitle, SetSubtitle and
+// SetActionBar, the times in ticks by SetTimesAndDisplay.
+type Title struct {
+	Action                TitleAction
+	Text                  Chat
+	FadeIn, Stay, FadeOut int32
+}
+
+// MarshalProtocol implements the Marshaler interface.
+func (p *Title) MarshalProtocol(e *Encoder) error {
+	if err := e.WriteVarInt(int32(p.Action)); err != nil {
+		return err
+	}
+
+	switch p.Action {
+	case SetTitle, SetSubtitle, SetActionBar:
+		return p.Text.MarshalProtocol(e)
+	case SetTimesAndDisplay:
+		for _, v := range []int32{p.FadeIn, p.Stay, p.FadeOut} {
+			if err := e.WriteUint32(uint32(v)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case HideTitle, ResetTitle:
+		return nil
+	}
+	return fmt.Errorf("protocol: unknown title action %d", p.Action)
+}
+
+// UnmarshalProtocol implements the Unmarshaler interface.
+func (p *Title) UnmarshalProtocol(d *Decoder) error {
+	*p = Title{}
+
+	action, err := d.ReadVarInt()
+	if err != nil {
+		return err
+	}
+	p.Action = TitleAction(action)
+
+	switch p.Action {
+	case SetTitle, SetSubtitle, SetActionBar:
+		return p.Text.UnmarshalProtocol(d)
+	case SetTimesAndDisplay:
+		for _, v := range []*int32{&p.FadeIn, &p.Stay, &p.FadeOut} {
+			n, err := d.ReadUint32()
+			if err != nil {
+				return err
+			}
+			*v = int32(n)
+		}
+		return nil
+	case HideTitle, ResetTitle:
+		return nil
+	}
+	return fmt.Errorf("protocol: unknown title action %d", p.Action)
+}
+
+// Flags of a StopSound packet.
+const (
+	StopSoundSource int8 = 0x01
+	StopSoundName   int8 = 0x02
+)
+
+// StopSound stops the sounds matching the source and sound name. Source is
+// only sent when Flags has StopSoundSource and Sound when it has
+// StopSoundName.
+type StopSound struct {
+	Flags  int8
+	Source int32
+	Sound  Identifier
+}
+
+// MarshalProtocol implements the Marshaler interface.
+func (p *StopSound) MarshalProtocol(e *Encoder) error {
+	if err := e.WriteByte(byte(p.Flags)); err != nil {
+		return err
+	}
+	if p.Flags&StopSoundSource != 0 {
+		if err := e.WriteVarInt(p.Source); err != nil {
+			return err
+		}
+	}
+	if p.Flags&StopSoundName != 0 {
+		return p.Sound.MarshalProtocol(e)
+	}
+	return nil
+}
+
+// UnmarshalProtocol implements the Unmarshaler interface.
+func (p *StopSound) UnmarshalProtocol(d *Decoder) error {
+	*p = StopSound{}
+
+	flags, err := d.ReadByte()
+	if err != nil {
+		return noEOF(err)
+	}
+	p.Flags = int8(flags)
+	if p.Flags&StopSoundSource != 0 {
+		if p.Source, err = d.ReadVarInt(); err != nil {
+			return err
+		}
+	}
+	if p.Flags&StopSoundName != 0 {
+		return p.Sound.UnmarshalProtocol(d)
+	}
+	return nil
+}
+
+// Flags of an AdvancementDisplay.
+const (
+	AdvancementHasBackground int32 = 0x01
+	AdvancementShowToast     int32 = 0x02
+	AdvancementHidden        int32 = 0x04
+)
+
+// AdvancementDisplay is how an advancement is shown in the advancement
+// screen. BackgroundTexture is only sent when Flags has
+// AdvancementHasBackground.
+type AdvancementDisplay struct {
+	Title             Chat
+	Description       Chat
+	Icon              Slot
+	FrameType         int32
+	Flags             int32
+	BackgroundTexture Identifier
+	X, Y              float32
+}
+
+// MarshalProtocol implements the Marshaler interface.
+func (a *AdvancementDisplay) MarshalProtocol(e *Encoder) error {
+	if err := a.Title.MarshalProtocol(e); err != nil {
+		return err
+	}
+	if err := a.Description.MarshalProtocol(e); err != nil {
+		return err
+	}
+	if err := a.Icon.MarshalProtocol(e); err != nil {
+		return err
+	}
+	if err := e.WriteVarInt(a.FrameType); err != nil {
+		return err
+	}
+	if err := e.WriteUint32(uint32(a.Flags)); err != nil {
+		return err
+	}
+	if a.Flags&AdvancementHasBackground != 0 {
+		if err := a.BackgroundTexture.MarshalProtocol(e); err != nil {
+			return err
+		}
+	}
+	if err := e.WriteFloat32(a.X); err != nil {
+		return err
+	}
+	return e.WriteFloat32(a.Y)
+}
+
+// UnmarshalProtocol implements the Unmarshaler interface.
+func (a *AdvancementDisplay) UnmarshalProtocol(d *Decoder) error {
+	*a = AdvancementDisplay{}
+
+	if err := a.Title.UnmarshalProtocol(d); err != nil {
+		return err
+	}
+	if err := a.Description.UnmarshalProtocol(d); err != nil {
+		return err
+	}
+	if err := a.Icon.UnmarshalProtocol(d); err != nil {
+		return err
+	}
+
+	var err error
+	if a.FrameType, err = d.ReadVarInt(); err != nil {
+		return err
+	}
+	flags, err := d.ReadUint32()
+	if err != nil {
+		return err
+	}
+	a.Flags = int32(flags)
+	if a.Flags&AdvancementHasBackground != 0 {
+		if err := a.BackgroundTexture.UnmarshalProtocol(d); err != nil {
+			return err
+		}
+	}
+	if a.X, err = d.ReadFloat32(); err != nil {
+		return err
+	}
+	a.Y, err = d.ReadFloat32()
+	return err
+}
+
+// Operations of an AttributeModifier.
+const (
+	ModifierAdd             int8 = 0
+	ModifierAddPercent      int8 = 1
+	ModifierMultiplyPercent int8 = 2
+)
+
+// Flags of an EntityEffect.
+const (
+	EffectAmbient       int8 = 0x01
+	EffectShowParticles int8 = 0x02
+	EffectShowIcon      int8 = 0x04
+)
+
+// Ingredient is the list of items accepted in a recipe slot.
+type Ingredient []Slot
+
+// Recipe types of protocol 404 (1.13.2) that carry data. The other types
+// are the crafting_special_* recipes.
+const (
+	RecipeShapeless Identifier = "minecraft:crafting_shapeless"
+	RecipeShaped    Identifier = "minecraft:crafting_shaped"
+	RecipeSmelting  Identifier = "minecraft:smelting"
+)
+
+// Recipe is a recipe of the DeclareRecipes packet. Only the fields used by
+// the recipe type are encoded.
+type Recipe struct {
+	ID   Identifier
+	Type Identifier
+
+	// Group, Ingredients and Result are used by RecipeShapeless, RecipeShaped
+	// and RecipeSmelting. Shaped recipes have Width*Height ingredients and
+	// smelting recipes exactly one.
+	Group       string
+	Ingredients []Ingredient
+	Result      Slot
+
+	// Width and Height are used by RecipeShaped.
+	Width, Height int32
+
+	// Experience and CookingTime are used by RecipeSmelting.
+	Experience  float32
+	CookingTime int32
+}
+
+// MarshalProtocol implements the Marshaler interface.
+func (r Recipe) MarshalProtocol(e *Encoder) error {
+	if err := r.ID.MarshalProtocol(e); err != nil {
+		return err
+	}
+	t, err := ParseIdentifier(string(r.Type))
+	if err != nil {
+		return err
+	}
+	if err := e.WriteString(string(r.Type)); err != nil {
+		return err
+	}
+
+	switch t {
+	case RecipeShapeless:
+		if err := e.WriteString(r.Group); err != nil {
+			return err
+		}
+		if err := e.Encode(r.Ingredients); err != nil {
+			return err
+		}
+	case RecipeShaped:
+		if int(r.Width)*int(r.Height) != len(r.Ingredients) || r.Width < 0 {
+			return fmt.Errorf("protocol: shaped recipe of %dx%d has %d ingredients", r.Width, r.Height, len(r.Ingredients))
+		}
+		if err := e.WriteVarInt(r.Width); err != nil {
+			return err
+		}
+		if err := e.WriteVarInt(r.Height); err != nil {
+			return err
+		}
+		if err := e.WriteString(r.Group); err != nil {
+			return err
+		}
+		for _, i := range r.Ingredients {
+			if err := e.Encode(i); err != nil {
+				return err
+			}
+		}
+	case RecipeSmelting:
+		if len(r.Ingredients) != 1 {
+			return fmt.Errorf("protocol: smelting recipe has %d ingredients", len(r.Ingredients))
+		}
+		if err := e.WriteString(r.Group); err != nil {
+			return err
+		}
+		if err := e.Encode(r.Ingredients[0]); err != nil {
+			return err
+		}
+		if err := r.Result.MarshalProtocol(e); err != nil {
+			return err
+		}
+		if err := e.WriteFloat32(r.Experience); err != nil {
+			return err
+		}
+		return e.WriteVarInt(r.CookingTime)
+	default:
+		return nil
+	}
+	return r.Result.MarshalProtocol(e)
+}
+
+// UnmarshalProtocol implements the Unmarshaler interface.
+func (r *Recipe) UnmarshalProtocol(d *Decoder) error {
+	*r = Recipe{}
+
+	if err := r.ID.UnmarshalProtocol(d); err != nil {
+		return err
+	}
+	if err := r.Type.UnmarshalProtocol(d); err != nil {
+		return err
+	}
+
+	var err error
+	switch r.Type {
+	case RecipeShapeless:
+		if r.Group, err = d.ReadString(MaxStringLength); err != nil {
+			return err
+		}
+		if err := d.Decode(&r.Ingredients); err != nil {
+			return err
+		}
+	case RecipeShaped:
+		if r.Width, err = d.ReadVarInt(); err != nil {
+			return err
+		}
+		if r.Height, err = d.ReadVarInt(); err != nil {
+			return err
+		}
+		if r.Width < 0 || r.Height < 0 || int64(r.Width)*int64(r.Height) > 9 {
+			return fmt.Errorf("protocol: invalid shaped recipe size %dx%d", r.Width, r.Height)
+		}
+		if r.Group, err = d.ReadString(MaxStringLength); err != nil {
+			return err
+		}
+		r.Ingredients = make([]Ingredient, r.Width*r.Height)
+		for i := range r.Ingredients {
+			if err := d.Decode(&r.Ingredients[i]); err != nil {
+				return err
+			}
+		}
+	case RecipeSmelting:
+		if r.Group, err = d.ReadString(MaxStringLength); err != nil {
+			return err
+		}
+		r.Ingredients = make([]Ingredient, 1)
+		if err := d.Decode(&r.Ingredients[0]); err != nil {
+			return err
+		}
+		if err := r.Result.UnmarshalProtocol(d); err != nil {
+			return err
+		}
+		if r.Experience, err = d.ReadFloat32(); err != nil {
+			return err
+		}
+		r.CookingTime, err = d.ReadVarInt()
+		return err
+	default:
+		return nil
+	}
+	return r.Result.UnmarshalProtocol(d)
+}
@@ -0,0 +1,37 @@
+package protocol
+
+// Position is the location of a block. It is encoded as a single Long with X
+// and Z using 26 bits and Y using 12 bits.
+// See https://wiki.vg/Protocol#Position for more info.
+type Position struct {
+	X, Y, Z int32
+}
+
+// Pack returns the Long representation of the Position.
+func (p Position) Pack() int64 {
+	return (int64(p.X)&0x3FFFFFF)<<38 | (int64(p.Y)&0xFFF)<<26 | int64(p.Z)&0x3FFFFFF
+}
+
+// UnpackPosition returns the Position represented by the Long v.
+func UnpackPosition(v int64) Position {
+	return Position{
+		X: int32(v >> 38),
+		Y: int32(v << 26 >> 52),
+		Z: int32(v << 38 >> 38),
+	}
+}
+
+// MarshalProtocol implements the Marshaler interface.
+func (p Position) MarshalProtocol(e *Encoder) error {
+	return e.WriteUint64(uint64(p.Pack()))
+}
+
+// UnmarshalProtocol implements the Unmarshaler interface.
+func (p *Position) UnmarshalProtocol(d *Decoder) error {
+	v, err := d.ReadUint64()
+	if err != nil {
+		return err
+	}
+	*p = UnpackPosition(int64(v))
+	return nil
+}
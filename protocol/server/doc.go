@@ -0,0 +1,4 @@
+// Package server contains the IDs of the serverbound packets of protocol 404
+// (1.13.2).
+// See https://wiki.vg/index.php?title=Protocol&oldid=14889 for more info.
+package server
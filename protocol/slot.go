@@ -0,0 +1,57 @@
+package protocol
+
+import (
+	"github.com/JDWardle/gocraft/nbt"
+)
+
+// Slot is an item stack in an inventory window, an item entity or a metadata
+// value. Empty slots have Present set to false.
+// See https://wiki.vg/Slot_Data for more info.
+type Slot struct {
+	Present bool
+	ItemID  int32
+	Count   int8
+	NBT     nbt.Compound
+}
+
+// MarshalProtocol implements the Marshaler interface.
+func (s Slot) MarshalProtocol(e *Encoder) error {
+	if err := e.WriteBool(s.Present); err != nil || !s.Present {
+		return err
+	}
+
+	if err := e.WriteVarInt(s.ItemID); err != nil {
+		return err
+	}
+	if err := e.WriteByte(byte(s.Count)); err != nil {
+		return err
+	}
+
+	return NBT(s.NBT).MarshalProtocol(e)
+}
+
+// UnmarshalProtocol implements the Unmarshaler interface.
+func (s *Slot) UnmarshalProtocol(d *Decoder) error {
+	*s = Slot{}
+
+	present, err := d.ReadBool()
+	if err != nil || !present {
+		return err
+	}
+	s.Present = true
+
+	if s.ItemID, err = d.ReadVarInt(); err != nil {
+		return err
+	}
+
+	count, err := d.ReadByte()
+	if err != nil {
+		return noEOF(err)
+	}
+	s.Count = int8(count)
+
+	var tag NBT
+	err = tag.UnmarshalProtocol(d)
+	s.NBT = nbt.Compound(tag)
+	return err
+}
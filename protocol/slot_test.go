@@ -0,0 +1,79 @@
+package protocol
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/JDWardle/gocraft/nbt"
+)
+
+func TestSlot(t *testing.T) {
+	tests := map[string]struct {
+		slot     Slot
+		expected []byte
+	}{
+		"empty": {Slot{}, []byte{0x00}},
+		"stone": {Slot{Present: true, ItemID: 1, Count: 64}, []byte{0x01, 0x01, 0x40, 0x00}},
+		"named": {
+			Slot{Present: true, ItemID: 280, Count: 1, NBT: nbt.Compound{"Damage": int32(5)}},
+			[]byte{0x01, 0x98, 0x02, 0x01, 0x0a, 0x00, 0x00, 0x03, 0x00, 0x06, 0x44, 0x61, 0x6d, 0x61, 0x67, 0x65, 0x00, 0x00, 0x00, 0x05, 0x00},
+		},
+	}
+
+	for name, test := range tests {
+		b, err := Marshal(test.slot)
+		if err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+
+		if !bytes.Equal(b, test.expected) {
+			t.Fatalf("Expected %s slot to be encoded as '%#02x' got '%#02x'", name, test.expected, b)
+		}
+
+		var s Slot
+		if err := Unmarshal(bytes.NewReader(b), &s); err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+
+		if !reflect.DeepEqual(s, test.slot) {
+			t.Fatalf("Expected '%#02x' to decode to '%+v' got '%+v'", b, test.slot, s)
+		}
+	}
+}
+
+func FuzzSlot(f *testing.F) {
+	f.Add([]byte{0x00})
+	f.Add([]byte{0x01, 0x01, 0x40, 0x00})
+	f.Add([]byte{0x01, 0x98, 0x02, 0x01, 0x0a, 0x00, 0x00, 0x03, 0x00, 0x06, 0x44, 0x61, 0x6d, 0x61, 0x67, 0x65, 0x00, 0x00, 0x00, 0x05, 0x00})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var s Slot
+		if err := Unmarshal(bytes.NewReader(data), &s); err != nil {
+			return
+		}
+		roundTrip(t, &s, &Slot{})
+	})
+}
+
+// roundTrip checks that v encodes, decodes into empty and encodes again to
+// the same bytes.
+func roundTrip(t *testing.T, v, empty interface{}) {
+	b, err := Marshal(v)
+	if err != nil {
+		return
+	}
+
+	if err := Unmarshal(bytes.NewReader(b), empty); err != nil {
+		t.Fatalf("Unexpected error decoding '%#02x': '%v'", b, err)
+	}
+
+	again, err := Marshal(empty)
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	if !bytes.Equal(b, again) {
+		t.Fatalf("Expected '%+v' to encode to '%#02x' got '%#02x'", empty, b, again)
+	}
+}
@@ -3,7 +3,12 @@ package protocol
 import (
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"io"
+	"math"
+	"unicode/utf8"
+
+	"github.com/gofrs/uuid"
 )
 
 // String encodes the passed in string as it's byte representation prefixed with
@@ -17,18 +22,35 @@ func String(s string) []byte {
 
 // ReadString reads bytes from a ByteReader and returns the string
 // representation of the bytes that were read. Strings are prefixed with the
-// length of the expected string as a VarInt.
+// length of the expected string as a VarInt. Returns an error if the string is
+// longer than MaxStringLength characters.
 // See https://wiki.vg/Protocol for more info.
 func ReadString(r io.ByteReader) (string, error) {
+	return ReadStringMax(r, MaxStringLength)
+}
+
+// ReadStringMax reads a String(max) from a ByteReader. Returns an error if the
+// string is longer than max characters or its length prefix could not possibly
+// hold a string of that size.
+// See https://wiki.vg/Protocol#Data_types for more info.
+func ReadStringMax(r io.ByteReader, max int) (string, error) {
 	n, err := ReadVarInt(r)
 	if err != nil {
 		return "", err
 	}
 
-	if n <= 0 {
+	if n < 0 {
+		return "", fmt.Errorf("negative string length %d", n)
+	}
+	if n == 0 {
 		return "", nil
 	}
 
+	// Each character is encoded as at most 4 bytes of UTF-8.
+	if int64(n) > int64(max)*4 {
+		return "", fmt.Errorf("string length %d exceeds maximum of %d", n, max)
+	}
+
 	s := make([]byte, n)
 	for i := range s {
 		b, err := r.ReadByte()
@@ -38,9 +60,117 @@ func ReadString(r io.ByteReader) (string, error) {
 		s[i] = b
 	}
 
+	if c := utf8.RuneCount(s); c > max {
+		return "", fmt.Errorf("string length %d exceeds maximum of %d", c, max)
+	}
+
 	return string(s), nil
 }
 
+// Boolean encodes a bool as a single byte, 0x01 for true and 0x00 for false.
+func Boolean(v bool) []byte {
+	if v {
+		return []byte{0x01}
+	}
+	return []byte{0x00}
+}
+
+// ReadBoolean reads a single byte from a ByteReader and returns the bool it
+// represents. Returns an error if the byte is not 0x00 or 0x01.
+func ReadBoolean(r io.ByteReader) (bool, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return false, err
+	}
+
+	switch b {
+	case 0x00:
+		return false, nil
+	case 0x01:
+		return true, nil
+	}
+
+	return false, fmt.Errorf("invalid Boolean %#02x", b)
+}
+
+// Float encodes a float32 as a big-endian IEEE 754 single-precision number.
+func Float(v float32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, math.Float32bits(v))
+	return b
+}
+
+// ReadFloat reads a big-endian IEEE 754 single-precision number from a
+// Reader.
+func ReadFloat(r io.Reader) (float32, error) {
+	var b [4]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return 0, err
+	}
+	return math.Float32frombits(binary.BigEndian.Uint32(b[:])), nil
+}
+
+// Double encodes a float64 as a big-endian IEEE 754 double-precision number.
+func Double(v float64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, math.Float64bits(v))
+	return b
+}
+
+// ReadDouble reads a big-endian IEEE 754 double-precision number from a
+// Reader.
+func ReadDouble(r io.Reader) (float64, error) {
+	var b [8]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return 0, err
+	}
+	return math.Float64frombits(binary.BigEndian.Uint64(b[:])), nil
+}
+
+// UUID encodes a UUID as an unsigned 128-bit integer, most significant half
+// first.
+func UUID(u uuid.UUID) []byte {
+	return u.Bytes()
+}
+
+// ReadUUID reads an unsigned 128-bit integer from a Reader and returns it as a
+// UUID.
+func ReadUUID(r io.Reader) (uuid.UUID, error) {
+	var u uuid.UUID
+	_, err := io.ReadFull(r, u[:])
+	return u, err
+}
+
+// ByteArray encodes a byte slice prefixed with its length as a VarInt.
+func ByteArray(b []byte) []byte {
+	return append(VarInt(int32(len(b))), b...)
+}
+
+// ReadByteArray reads a byte slice prefixed with its length as a VarInt from a
+// Reader. Returns an error if the length is negative or larger than max.
+func ReadByteArray(r io.Reader, max int) ([]byte, error) {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = &byteReader{r: r}
+	}
+
+	n, err := ReadVarInt(br)
+	if err != nil {
+		return nil, err
+	}
+
+	if n < 0 || int64(n) > int64(max) {
+		return nil, fmt.Errorf("byte array length %d exceeds maximum of %d", n, max)
+	}
+
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
 // SizeVarInt returns the size in bytes that is needed to represent an int32 as
 // a VarInt.
 func SizeVarInt(x uint32) int {
@@ -183,117 +313,35 @@ func ReadVarLong(r io.ByteReader) (int64, error) {
 	return int64(res), nil
 }
 
-// Following encoding/xml for implementing the Protocol marshaler/unmarshaler
-
-// type VarInt int32
-
-// func (v VarInt) MarshalProtocol(e *Encoder) error {
-// 	return errors.New("not implemented")
-// }
-
-// func (v *VarInt) UnmarshalProtocol(d *Decoder) error {
-// 	return errors.New("not implemented")
-// }
-
-// type String string
-
-// func (s String) MarshalProtocol(e *Encoder) error {
-// 	return errors.New("not implemented")
-// }
-
-// func (s *String) UnmarshalProtocol(d *Decoder) error {
-// 	return errors.New("not implemented")
-// }
-
-// type Decoder struct {
-// 	io.ByteReader
-// }
-
-// func NewDecoder(r io.Reader) *Decoder {
-// 	d := &Decoder{}
-// 	if rb, ok := r.(io.ByteReader); ok {
-// 		d.ByteReader = rb
-// 	} else {
-// 		d.ByteReader = bufio.NewReader(r)
-// 	}
-// 	return d
-// }
-
-// type Encoder struct {
-// 	io.ByteWriter
-// }
-
-// func NewWriter(w io.Writer) *Encoder {
-// 	return &Encoder{
-// 		bufio.NewWriter(w),
-// 	}
-// }
-
-// type Marshaler interface {
-// 	MarshalProtocol(e *Encoder) error
-// }
-
-// type Unmarshaler interface {
-// 	UnmarshalProtocol(d *Decoder) error
-// }
-
-// type ProtocolID int32
-
-// type String string
-
-// func ReadString(r io.ByteReader) (string, error) {
-// 	n, err := binary.ReadUvarint(r)
-// 	if err != nil {
-// 		return "", nil
-// 	}
-
-// 	if n == 0 {
-// 		return "", nil
-// 	}
-
-// 	s := make([]byte, n)
-// 	for i := range s {
-// 		b, err := r.ReadByte()
-// 		if err != nil {
-// 			return "", err
-// 		}
-// 		s[i] = b
-// 	}
-
-// 	return string(s), nil
-// }
-
-// func PutString(r *bytes.Reader) error {
-
-// }
-
-// Should be handled:
-
-// Boolean bool binary.Read
-// Byte int8 binary.Read
-// UnsignedByte uint8 binary.Read
-// Short int16 binary.Read
-// UnsignedShort uint16 binary.Read
-// Int int32 binary.Read
-// Long int64 binary.Read
-// Angle int8 binary.Read
+// Handled by Marshal/Unmarshal from the Go type:
+// Boolean bool
+// Byte int8
+// UnsignedByte uint8
+// Short int16
+// UnsignedShort uint16
+// Int int32
+// Long int64
+// Float float32
+// Double float64
+// Angle protocol.Angle
+// UUID uuid.UUID
+// Optional X *X `mc:",optional"`
+// Array of X []X, prefixed with a VarInt count unless tagged `mc:",len=..."`
+// X Enum named integer type, tagged `mc:"varint"` for VarInt enums
+// Byte Array []byte, `mc:",rest"` when it fills the rest of the packet
 
 // Handled with custom implementation:
-// String(n) string protocol.String protocol.ReadString
+// String(n) string protocol.String protocol.ReadString protocol.ReadStringMax
 // VarInt int32 protocol.VarInt protocol.ReadVarInt
 // VarLong int64 protocol.VarLong protocol.ReadVarLong
-
-// Needs custom implementation:
-// Float float32 custom? or binary.Read
-// Double float64 custom? or binary.Read
-// Chat JSON custom lenient decoding? max length 32767 (bytes? bits? characters?)
-// Identifier string custom max length 32767 (bytes? bits? characters?)
-// EntityMetadata []byte custom https://wiki.vg/Entity_metadata#Entity_Metadata_Format
-// Slot []byte https://wiki.vg/Slot_Data
-// NBTTag []byte https://wiki.vg/NBT
-// Position int64 custom https://wiki.vg/Protocol#Position
-// UUID string custom 128 bit integer
-// Optional X custom
-// Array of X custom
-// X Enum custom
-// Byte Array []byte custom
+// Position int64 protocol.Position
+// Identifier string protocol.Identifier
+// Boolean bool protocol.Boolean protocol.ReadBoolean
+// Float float32 protocol.Float protocol.ReadFloat
+// Double float64 protocol.Double protocol.ReadDouble
+// UUID uuid.UUID protocol.UUID protocol.ReadUUID
+// Byte Array []byte protocol.ByteArray protocol.ReadByteArray
+// NBTTag []byte nbt.Marshal nbt.Unmarshal
+// Chat JSON protocol.Chat
+// EntityMetadata []byte protocol.Metadata
+// Slot []byte protocol.Slot
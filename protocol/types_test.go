@@ -6,6 +6,8 @@ import (
 	"math"
 	"reflect"
 	"testing"
+
+	"github.com/gofrs/uuid"
 )
 
 func TestString(t *testing.T) {
@@ -153,6 +155,217 @@ func TestReadVarLong(t *testing.T) {
 	}
 }
 
+func TestReadStringMax(t *testing.T) {
+	tests := map[int][]byte{
+		4: []byte{0x04, 0x74, 0x65, 0x73, 0x74},
+		1: []byte{0x04, 0xf0, 0x9f, 0x98, 0x80},
+	}
+
+	for max, test := range tests {
+		r := bytes.NewReader(test)
+		if _, err := ReadStringMax(r, max); err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+	}
+
+	errorTests := map[int][]byte{
+		3: []byte{0x04, 0x74, 0x65, 0x73, 0x74},
+		1: []byte{0x05, 0x74, 0x65, 0x73, 0x74, 0x74},
+	}
+
+	for max, test := range errorTests {
+		r := bytes.NewReader(test)
+		if s, err := ReadStringMax(r, max); err == nil {
+			t.Fatalf("Expected '%#02x' to exceed String(%d) got '%s'", test, max, s)
+		}
+	}
+
+	// A negative length of -1.
+	r := bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff, 0x0f})
+	if s, err := ReadStringMax(r, 4); err == nil {
+		t.Fatalf("Expected an error for a negative length got '%s'", s)
+	}
+}
+
+func TestBoolean(t *testing.T) {
+	tests := map[bool][]byte{
+		true:  []byte{0x01},
+		false: []byte{0x00},
+	}
+
+	for test, expected := range tests {
+		b := Boolean(test)
+
+		if !reflect.DeepEqual(b, expected) {
+			t.Fatalf("Expected '%t' to be encoded as '%#02x' got '%#02x'", test, expected, b)
+		}
+
+		v, err := ReadBoolean(bytes.NewReader(expected))
+		if err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+
+		if v != test {
+			t.Fatalf("Expected '%#02x' to decode to '%t' got '%t'", expected, test, v)
+		}
+	}
+
+	if _, err := ReadBoolean(bytes.NewReader([]byte{0x02})); err == nil {
+		t.Fatalf("Expected an error decoding '0x02'")
+	}
+}
+
+func TestFloat(t *testing.T) {
+	tests := map[float32][]byte{
+		0:                           []byte{0x00, 0x00, 0x00, 0x00},
+		1:                           []byte{0x3f, 0x80, 0x00, 0x00},
+		-2.5:                        []byte{0xc0, 0x20, 0x00, 0x00},
+		math.MaxFloat32:             []byte{0x7f, 0x7f, 0xff, 0xff},
+		math.SmallestNonzeroFloat32: []byte{0x00, 0x00, 0x00, 0x01},
+	}
+
+	for test, expected := range tests {
+		b := Float(test)
+
+		if !reflect.DeepEqual(b, expected) {
+			t.Fatalf("Expected '%g' to be encoded as '%#02x' got '%#02x'", test, expected, b)
+		}
+
+		v, err := ReadFloat(bytes.NewReader(expected))
+		if err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+
+		if v != test {
+			t.Fatalf("Expected '%#02x' to decode to '%g' got '%g'", expected, test, v)
+		}
+	}
+}
+
+func TestDouble(t *testing.T) {
+	tests := map[float64][]byte{
+		0:               []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		1:               []byte{0x3f, 0xf0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		-2.5:            []byte{0xc0, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		math.MaxFloat64: []byte{0x7f, 0xef, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+	}
+
+	for test, expected := range tests {
+		b := Double(test)
+
+		if !reflect.DeepEqual(b, expected) {
+			t.Fatalf("Expected '%g' to be encoded as '%#02x' got '%#02x'", test, expected, b)
+		}
+
+		v, err := ReadDouble(bytes.NewReader(expected))
+		if err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+
+		if v != test {
+			t.Fatalf("Expected '%#02x' to decode to '%g' got '%g'", expected, test, v)
+		}
+	}
+}
+
+func TestAngle(t *testing.T) {
+	tests := map[float64]Angle{
+		0:      0,
+		90:     64,
+		180:    128,
+		270:    192,
+		360:    0,
+		-90:    192,
+		45.1:   32,
+		719.99: 0,
+	}
+
+	for test, expected := range tests {
+		if a := AngleFromDegrees(test); a != expected {
+			t.Fatalf("Expected '%g' degrees to be '%d' got '%d'", test, expected, a)
+		}
+	}
+
+	if d := Angle(64).Degrees(); d != 90 {
+		t.Fatalf("Expected '64' to be '90' degrees got '%g'", d)
+	}
+}
+
+func TestParseIdentifier(t *testing.T) {
+	tests := map[string]Identifier{
+		"stone":                      "minecraft:stone",
+		"minecraft:stone":            "minecraft:stone",
+		"gocraft:textures/block.png": "gocraft:textures/block.png",
+		"my-mod:item_1.0":            "my-mod:item_1.0",
+	}
+
+	for test, expected := range tests {
+		id, err := ParseIdentifier(test)
+		if err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+
+		if id != expected {
+			t.Fatalf("Expected '%s' to parse as '%s' got '%s'", test, expected, id)
+		}
+	}
+
+	errorTests := []string{"", ":stone", "minecraft:", "Minecraft:stone", "minecraft:Stone", "a/b:stone", "minecraft:stone block"}
+
+	for _, test := range errorTests {
+		if id, err := ParseIdentifier(test); err == nil {
+			t.Fatalf("Expected '%s' to be invalid got '%s'", test, id)
+		}
+	}
+}
+
+func TestUUID(t *testing.T) {
+	u := uuid.Must(uuid.FromString("069a79f4-44e9-4726-a5be-fca90e38aaf5"))
+	expected := []byte{0x06, 0x9a, 0x79, 0xf4, 0x44, 0xe9, 0x47, 0x26, 0xa5, 0xbe, 0xfc, 0xa9, 0x0e, 0x38, 0xaa, 0xf5}
+
+	b := UUID(u)
+	if !reflect.DeepEqual(b, expected) {
+		t.Fatalf("Expected '%s' to be encoded as '%#02x' got '%#02x'", u, expected, b)
+	}
+
+	v, err := ReadUUID(bytes.NewReader(expected))
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	if v != u {
+		t.Fatalf("Expected '%#02x' to decode to '%s' got '%s'", expected, u, v)
+	}
+}
+
+func TestByteArray(t *testing.T) {
+	tests := map[string][]byte{
+		"":         []byte{0x00},
+		"\x01\x02": []byte{0x02, 0x01, 0x02},
+	}
+
+	for test, expected := range tests {
+		b := ByteArray([]byte(test))
+
+		if !reflect.DeepEqual(b, expected) {
+			t.Fatalf("Expected '%#02x' to be encoded as '%#02x' got '%#02x'", test, expected, b)
+		}
+
+		v, err := ReadByteArray(bytes.NewReader(expected), 2)
+		if err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+
+		if string(v) != test {
+			t.Fatalf("Expected '%#02x' to decode to '%#02x' got '%#02x'", expected, test, v)
+		}
+	}
+
+	if _, err := ReadByteArray(bytes.NewReader([]byte{0x03, 0x01, 0x02, 0x03}), 2); err == nil {
+		t.Fatalf("Expected an error decoding a byte array larger than the maximum")
+	}
+}
+
 func benchmarkVarInt(v int32, b *testing.B) {
 	for n := 0; n < b.N; n++ {
 		VarInt(v)
@@ -0,0 +1,89 @@
+package protocol
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Version is a version of the protocol with the packets of each direction.
+type Version struct {
+	// Protocol is the protocol version number sent in the Handshake, e.g.
+	// 404.
+	Protocol int32
+	// Name is the Minecraft version of the protocol, e.g. "1.13.2".
+	Name string
+
+	ServerPackets *Packets
+	ClientPackets *Packets
+}
+
+func (v *Version) String() string {
+	return fmt.Sprintf("%s (%d)", v.Name, v.Protocol)
+}
+
+// Versions maps protocol version numbers to their Version.
+type Versions struct {
+	m map[int32]*Version
+	sync.RWMutex
+}
+
+// NewVersions returns the registry of the versions.
+func NewVersions(versions ...*Version) *Versions {
+	v := &Versions{m: make(map[int32]*Version)}
+	for _, version := range versions {
+		v.Register(version)
+	}
+	return v
+}
+
+// Register adds version, replacing the version with the same protocol number.
+func (v *Versions) Register(version *Version) {
+	v.Lock()
+	defer v.Unlock()
+
+	v.m[version.Protocol] = version
+}
+
+// Get returns the version of the protocol version number.
+func (v *Versions) Get(protocol int32) (*Version, bool) {
+	v.RLock()
+	defer v.RUnlock()
+
+	version, ok := v.m[protocol]
+	return version, ok
+}
+
+// List returns the versions from the oldest to the newest.
+func (v *Versions) List() []*Version {
+	v.RLock()
+	defer v.RUnlock()
+
+	versions := make([]*Version, 0, len(v.m))
+	for _, version := range v.m {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Protocol < versions[j].Protocol })
+	return versions
+}
+
+// Range describes the supported versions for players, e.g. "1.13.2" or
+// "1.12.2-1.14".
+func (v *Versions) Range() string {
+	versions := v.List()
+	switch len(versions) {
+	case 0:
+		return "none"
+	case 1:
+		return versions[0].Name
+	}
+	return versions[0].Name + "-" + versions[len(versions)-1].Name
+}
+
+// ServerPackets are the packets sent by the client to the server in the
+// DefaultVersion.
+var ServerPackets = DefaultVersion.ServerPackets
+
+// ClientPackets are the packets sent by the server to the client in the
+// DefaultVersion.
+var ClientPackets = DefaultVersion.ClientPackets
@@ -0,0 +1,29 @@
+package protocol
+
+import "testing"
+
+func TestVersions(t *testing.T) {
+	v := NewVersions(&Version{Protocol: 404, Name: "1.13.2"}, &Version{Protocol: 340, Name: "1.12.2"})
+
+	if version, ok := v.Get(340); !ok || version.Name != "1.12.2" {
+		t.Fatalf("Expected protocol 340 to be '1.12.2' got '%v'", version)
+	}
+	if _, ok := v.Get(477); ok {
+		t.Fatalf("Expected protocol 477 to be unsupported")
+	}
+
+	tests := map[*Versions]string{
+		NewVersions(): "none",
+		NewVersions(&Version{Protocol: 404, Name: "1.13.2"}): "1.13.2",
+		v: "1.12.2-1.13.2",
+	}
+	for versions, expected := range tests {
+		if got := versions.Range(); got != expected {
+			t.Fatalf("Expected '%s' got '%s'", expected, got)
+		}
+	}
+
+	if DefaultVersion.Protocol != 404 || ServerPackets != DefaultVersion.ServerPackets {
+		t.Fatalf("Expected the default version to be protocol 404 got %d", DefaultVersion.Protocol)
+	}
+}
@@ -2,78 +2,274 @@ package server
 
 import (
 	"bufio"
-	"fmt"
+	"context"
+	"errors"
 	"net"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/JDWardle/gocraft/auth"
+	"github.com/JDWardle/gocraft/capture"
 	"github.com/JDWardle/gocraft/protocol"
 )
 
 type Client struct {
 	ID    int
 	State protocol.ClientState
-	conn  net.Conn
+	// Version is the protocol version of the client. It is the
+	// protocol.DefaultVersion until the client sends a supported version in
+	// its Handshake.
+	Version *protocol.Version
+	Config  Config
+	// Handlers handles the packets of the client, DefaultHandlers unless set
+	// otherwise.
+	Handlers *Mux
+	// Profile is the profile of the player once authenticated.
+	Profile *auth.Profile
+	conn    net.Conn
+	r       *bufio.Reader
+	reader  *protocol.FrameReader
+	// server is the Server which accepted the client, if any.
+	server *Server
+
+	// mu orders the packets queued from any goroutine and guards State,
+	// Version and the start of the writer.
+	mu sync.Mutex
+	// queue holds the packets sent to the client until they are written by
+	// writeLoop, which is started by the first packet and closes written
+	// once it is done.
+	queue     chan outbound
+	written   chan struct{}
+	closed    chan struct{}
+	closeOnce sync.Once
+	// ctx is cancelled once the client is closed, to give up on the
+	// requests made for it.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	keepAlive keepAlive
+	errors    errorCounters
+
+	// Fields of the logger, see Logger.
+	tracing     atomic.Bool
+	logMu       sync.Mutex
+	logState    protocol.ClientState
+	logUsername string
+
+	recorder atomic.Pointer[capture.Writer]
+
+	handshake protocol.Handshake
+
+	// State of the login. nextMessageID, pluginRequests and authenticating
+	// are guarded by mu.
+	loginName      string
+	verifyToken    []byte
+	nextMessageID  int32
+	pluginRequests map[int32]pluginRequest
+	// authenticating is set once the login plugins are done.
+	authenticating bool
 }
 
 func NewClient(id int, conn net.Conn) *Client {
+	r := bufio.NewReader(conn)
+	ctx, cancel := context.WithCancel(context.Background())
 	return &Client{
-		ID:    id,
-		State: protocol.ClientStateHandshaking,
-		conn:  conn,
+		ID:       id,
+		State:    protocol.ClientStateHandshaking,
+		Version:  protocol.DefaultVersion,
+		Config:   DefaultConfig,
+		Handlers: DefaultHandlers,
+		conn:     conn,
+		r:        r,
+		reader:   protocol.NewFrameReader(r),
+		closed:   make(chan struct{}),
+		ctx:      ctx,
+		cancel:   cancel,
 	}
 }
 
 func (c *Client) HandleMessages() {
 	defer c.Close()
 
-	r := bufio.NewReader(c.conn)
+	// Lifted once the player joins the game, Keep Alive detects dead
+	// connections from then on.
+	c.conn.SetReadDeadline(time.Now().Add(c.Config.loginTimeout()))
 
 	for {
+		// This is more than likely a legacy server list ping which is not
+		// length prefixed, the first byte is the ID of the packet.
+		if c.State == protocol.ClientStateHandshaking {
+			if b, err := c.r.Peek(1); err == nil && b[0] == 0xFE {
+				ok, h := c.Handlers.GetHandler(c.State, 0xFE)
+				if !ok {
+					c.Logger().Debug("unhandled legacy server list ping")
+					break
+				}
+
+				c.trace("legacy server list ping received")
+				if err := h(c, c.r); err != nil {
+					c.Logger().Warn("legacy server list ping failed", "err", err)
+				}
+				break
+			}
+		}
+
 		// This will block until a request is sent from the client.
-		packetLength, err := protocol.ReadVarInt(r)
+		frame, err := c.reader.ReadFrame()
 		if err != nil {
-			fmt.Println(err)
+			if errors.Is(err, protocol.ErrPacketTooLarge) {
+				c.handleError(&PacketError{State: c.State, Err: err})
+			} else {
+				c.Logger().Debug("connection closed", "err", err)
+			}
 			break
 		}
 
-		// This is more than likely a legacy server list ping so the first byte
-		// was the ID of the packet.
-		if c.State == protocol.ClientStateHandshaking && packetLength == 0xFE {
-			ok, h := DefaultHandlers.GetHandler(c.State, packetLength)
-			if !ok {
-				fmt.Printf("unknown packet ID %#02x\n", packetLength)
-				continue
-			}
+		c.trace("packet received", "id", frame.ID, "length", protocol.SizeVarInt(uint32(frame.ID))+len(frame.Data))
+		c.record(capture.Serverbound, c.State, frame.ID, frame.Data)
 
-			err = h(c, r)
-			if err := h(c, r); err != nil {
-				fmt.Println(err)
+		state := c.State
+		known, p := c.Version.ServerPackets.GetPacket(state, frame.ID)
+		if !known {
+			if c.handleError(&PacketError{State: state, ID: frame.ID, Err: ErrUnknownPacket}) {
+				continue
 			}
-			continue
+			break
 		}
 
-		packetID, err := protocol.ReadVarInt(r)
-		if err != nil {
-			fmt.Println(err)
-			continue
+		// The handlers are registered with the IDs of the default version.
+		id, ok := protocol.ServerPackets.IDByName(state, protocol.PacketName(p))
+		var h HandlerFunc
+		if ok {
+			ok, h = c.Handlers.GetHandler(state, id)
 		}
-
-		fmt.Println("Packet length:", packetLength)
-		fmt.Printf("Packet ID: %#02x\n", packetID)
-
-		ok, h := DefaultHandlers.GetHandler(c.State, packetID)
 		if !ok {
-			fmt.Printf("unknown packet ID %#02x\n", packetID)
+			c.Logger().Debug("unhandled packet", "id", frame.ID)
 			continue
 		}
 
-		if err := h(c, r); err != nil {
-			fmt.Println(err)
-			continue
+		if err := h(c, frame.Reader()); err != nil {
+			var packetErr *PacketError
+			if !errors.As(err, &packetErr) {
+				err = &PacketError{State: state, ID: frame.ID, Err: err}
+			}
+			if c.handleError(err) {
+				continue
+			}
+			break
+		}
+
+		if n := frame.Remaining(); n > 0 {
+			c.Logger().Warn("packet not read entirely", "id", frame.ID, "unread", n)
 		}
 	}
 }
 
+// Record records every packet received and sent from now on to w until it is
+// called with nil. The legacy server list ping is not recorded. It is safe to
+// call from any goroutine but is usually called from Server.OnConnect.
+func (c *Client) Record(w *capture.Writer) {
+	c.recorder.Store(w)
+}
+
+// Recorder returns the writer of the packets set by Record, if any.
+func (c *Client) Recorder() *capture.Writer {
+	return c.recorder.Load()
+}
+
+// record writes the packet to the recorder of the client, if any.
+func (c *Client) record(direction capture.Direction, state protocol.ClientState, id int32, data []byte) {
+	w := c.recorder.Load()
+	if w == nil {
+		return
+	}
+
+	err := w.Write(&capture.Record{
+		Time:      time.Now(),
+		Direction: direction,
+		State:     state,
+		Protocol:  c.Version.Protocol,
+		ID:        id,
+		Data:      data,
+	})
+	if err != nil {
+		c.Logger().Warn("recording failed, stopping the capture", "err", err)
+		c.recorder.CompareAndSwap(w, nil)
+	}
+}
+
+// SetCompression sends Set Compression to the client and switches to the
+// compressed packet format, compressing the packets of at least threshold
+// bytes. Nothing is done when threshold is negative.
+func (c *Client) SetCompression(threshold int) error {
+	if threshold < 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.send(&protocol.SetCompression{Threshold: int32(threshold)}); err != nil {
+		return err
+	}
+	c.reader.SetCompression(threshold)
+	return c.enqueue(outbound{apply: func(w *protocol.FrameWriter) {
+		w.SetCompression(threshold)
+	}})
+}
+
+// setState switches the client to the state s.
+func (c *Client) setState(s protocol.ClientState) {
+	c.mu.Lock()
+	c.State = s
+	c.mu.Unlock()
+
+	c.setLogFields(s, "")
+}
+
+// Disconnect sends reason to the client in a Disconnect packet of its state
+// and closes the connection. Clients in the other states are disconnected
+// without a reason. It is safe to call from any goroutine.
+func (c *Client) Disconnect(reason protocol.Chat) error {
+	c.mu.Lock()
+	var err error
+	switch c.State {
+	case protocol.ClientStateLogin:
+		err = c.send(&protocol.LoginDisconnect{Reason: reason})
+	case protocol.ClientStatePlay:
+		err = c.send(&protocol.Disconnect{Reason: reason})
+	}
+	writing := c.queue != nil
+	c.mu.Unlock()
+
+	// The writer closes the connection once the reason is sent.
+	c.close()
+	if !writing {
+		c.conn.Close()
+	}
+	return err
+}
+
+// Close flushes the packets queued for the client and closes the connection.
 func (c *Client) Close() {
-	fmt.Printf("client %s disconnected\n", c.conn.RemoteAddr())
+	c.Logger().Info("client disconnected")
+
+	c.mu.Lock()
+	writing := c.queue != nil
+	c.mu.Unlock()
+
+	c.close()
+	if writing {
+		<-c.written
+	}
 	c.conn.Close()
 }
+
+// close stops the writer of the client once the queued packets are sent.
+func (c *Client) close() {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		c.cancel()
+	})
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"crypto/rsa"
+	"log/slog"
+	"time"
+
+	"github.com/JDWardle/gocraft/auth"
+	"github.com/JDWardle/gocraft/protocol"
+)
+
+// Config holds the options of the server.
+type Config struct {
+	// CompressionThreshold is the size in bytes from which packets are
+	// compressed, DefaultCompressionThreshold when 0. Set Compression is sent
+	// during login unless it is negative.
+	CompressionThreshold int
+
+	// OnlineMode enables the encryption of the connections.
+	OnlineMode bool
+	// PrivateKey is the RSA key used to exchange the shared secret of the
+	// encryption. A key of KeySize bits is generated when it is nil.
+	PrivateKey *rsa.PrivateKey
+	// SessionVerifier authenticates the players in online mode. The Mojang
+	// session service is used when it is nil.
+	SessionVerifier auth.SessionVerifier
+
+	// StatusProvider builds the status shown in the server list,
+	// DefaultStatus when it is nil.
+	StatusProvider StatusProvider
+
+	// LoginPlugins are the plugins taking part in the logins by channel.
+	LoginPlugins map[protocol.Identifier]LoginPlugin
+
+	// SendQueueSize is the number of packets queued for each client before
+	// it is disconnected for not keeping up, DefaultSendQueueSize when 0.
+	SendQueueSize int
+	// FlushInterval is the interval at which the queued packets are flushed
+	// to the connection, DefaultFlushInterval when 0.
+	FlushInterval time.Duration
+	// WriteTimeout is the time after which a write to a client fails and
+	// closes its connection, DefaultWriteTimeout when 0.
+	WriteTimeout time.Duration
+
+	// ErrorPolicy decides what is done with a client after an error,
+	// DefaultErrorPolicy when nil.
+	ErrorPolicy ErrorPolicy
+
+	// Logger receives the logs of the server and its clients, slog.Default()
+	// when nil. See NewLogger.
+	Logger *slog.Logger
+
+	// LoginTimeout is the time given to the clients to log in, or to get the
+	// status of the server, DefaultLoginTimeout when 0.
+	LoginTimeout time.Duration
+	// KeepAliveInterval is the interval at which Keep Alive is sent to the
+	// players, DefaultKeepAliveInterval when 0.
+	KeepAliveInterval time.Duration
+	// KeepAliveTimeout is the time after which a player that did not answer
+	// a Keep Alive is disconnected, DefaultKeepAliveTimeout when 0.
+	KeepAliveTimeout time.Duration
+}
+
+// DefaultCompressionThreshold is the size in bytes from which packets are
+// compressed when the Config has no CompressionThreshold, as done by vanilla
+// servers.
+const DefaultCompressionThreshold = 256
+
+// DefaultConfig is the Config used by clients unless set otherwise. It uses
+// the same values as vanilla servers.
+var DefaultConfig = Config{
+	CompressionThreshold: DefaultCompressionThreshold,
+	OnlineMode:           true,
+}
+
+func (c *Config) compressionThreshold() int {
+	if c.CompressionThreshold == 0 {
+		return DefaultCompressionThreshold
+	}
+	return c.CompressionThreshold
+}
+
+func (c *Config) sessionVerifier() auth.SessionVerifier {
+	if c.SessionVerifier == nil {
+		return &auth.SessionService{}
+	}
+	return c.SessionVerifier
+}
+
+func (c *Config) sendQueueSize() int {
+	if c.SendQueueSize <= 0 {
+		return DefaultSendQueueSize
+	}
+	return c.SendQueueSize
+}
+
+func (c *Config) flushInterval() time.Duration {
+	if c.FlushInterval <= 0 {
+		return DefaultFlushInterval
+	}
+	return c.FlushInterval
+}
+
+func (c *Config) writeTimeout() time.Duration {
+	if c.WriteTimeout <= 0 {
+		return DefaultWriteTimeout
+	}
+	return c.WriteTimeout
+}
+
+func (c *Config) loginTimeout() time.Duration {
+	if c.LoginTimeout <= 0 {
+		return DefaultLoginTimeout
+	}
+	return c.LoginTimeout
+}
+
+func (c *Config) keepAliveInterval() time.Duration {
+	if c.KeepAliveInterval <= 0 {
+		return DefaultKeepAliveInterval
+	}
+	return c.KeepAliveInterval
+}
+
+func (c *Config) keepAliveTimeout() time.Duration {
+	if c.KeepAliveTimeout <= 0 {
+		return DefaultKeepAliveTimeout
+	}
+	return c.KeepAliveTimeout
+}
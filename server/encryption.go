@@ -0,0 +1,59 @@
+package server
+
+import (
+	"crypto/aes"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"sync"
+
+	"github.com/JDWardle/gocraft/protocol"
+)
+
+// KeySize is the size in bits of the RSA keys used for the encryption of the
+// logins, as used by vanilla servers.
+const KeySize = 1024
+
+// The key used when the Config does not have one is generated on the first
+// online mode login.
+var (
+	defaultKey     *rsa.PrivateKey
+	defaultKeyErr  error
+	defaultKeyOnce sync.Once
+)
+
+// privateKey returns the PrivateKey of the config, or a key generated for the
+// lifetime of the process when it is nil.
+func (c *Config) privateKey() (*rsa.PrivateKey, error) {
+	if c.PrivateKey != nil {
+		return c.PrivateKey, nil
+	}
+
+	defaultKeyOnce.Do(func() {
+		defaultKey, defaultKeyErr = rsa.GenerateKey(rand.Reader, KeySize)
+	})
+	return defaultKey, defaultKeyErr
+}
+
+// enableEncryption encrypts the rest of the connection with AES/CFB8 using the
+// shared secret as the key and IV.
+func (c *Client) enableEncryption(secret []byte) error {
+	if len(secret) != 16 {
+		return fmt.Errorf("invalid shared secret length %d", len(secret))
+	}
+
+	block, err := aes.NewCipher(secret)
+	if err != nil {
+		return err
+	}
+
+	c.reader.SetCipher(protocol.NewCFB8Decrypter(block, secret))
+	encrypter := protocol.NewCFB8Encrypter(block, secret)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.enqueue(outbound{apply: func(w *protocol.FrameWriter) {
+		w.SetCipher(encrypter)
+	}})
+}
@@ -0,0 +1,175 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/JDWardle/gocraft/protocol"
+)
+
+var (
+	// ErrUnknownPacket is the error of a packet ID that does not exist in
+	// the state and version of the client.
+	ErrUnknownPacket = errors.New("server: unknown packet")
+	// ErrPacketTooLarge is the error of a packet longer than
+	// protocol.MaxPacketLength.
+	ErrPacketTooLarge = protocol.ErrPacketTooLarge
+	// ErrBadState is the error of a packet that is not expected at this
+	// point of the exchange, e.g. an Encryption Response that was not
+	// requested.
+	ErrBadState = errors.New("server: unexpected packet")
+	// ErrDecode is the error of a packet that could not be decoded.
+	ErrDecode = errors.New("server: invalid packet")
+)
+
+// PacketError is an error handling a packet of a client. Its Err matches one
+// of ErrUnknownPacket, ErrPacketTooLarge, ErrBadState and ErrDecode with
+// errors.Is when it is a protocol violation.
+type PacketError struct {
+	State protocol.ClientState
+	// ID is the packet ID, it is 0 when the packet could not be read.
+	ID int32
+	// Packet is the packet being handled, nil when it was not decoded.
+	Packet protocol.Handler
+	Err    error
+}
+
+func (e *PacketError) Error() string {
+	if e.Packet != nil {
+		return fmt.Sprintf("packet %T (%#02x) of state %d: %v", e.Packet, e.ID, e.State, e.Err)
+	}
+	return fmt.Sprintf("packet %#02x of state %d: %v", e.ID, e.State, e.Err)
+}
+
+func (e *PacketError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorAction is what is done with a client after an error.
+type ErrorAction int
+
+const (
+	// ContinueOnError logs the error and handles the next packet.
+	ContinueOnError ErrorAction = iota
+	// KickOnError sends the reason in a Disconnect packet of the state of
+	// the client before closing the connection.
+	KickOnError
+	// CloseOnError closes the connection without a reason.
+	CloseOnError
+)
+
+// ErrorPolicy decides what is done with the client c after the error err,
+// with the reason shown when it is kicked.
+type ErrorPolicy func(c *Client, err error) (action ErrorAction, reason protocol.Chat)
+
+// DefaultErrorPolicy is the ErrorPolicy used when the Config has none. The
+// clients violating the protocol are kicked, or disconnected when the
+// following packets cannot be read, and the other errors are only logged.
+func DefaultErrorPolicy(c *Client, err error) (ErrorAction, protocol.Chat) {
+	switch {
+	case errors.Is(err, ErrPacketTooLarge):
+		return CloseOnError, protocol.Chat{}
+	case errors.Is(err, ErrDecode):
+		return KickOnError, protocol.Text("Internal Exception: " + err.Error())
+	case errors.Is(err, ErrUnknownPacket):
+		var p *PacketError
+		if errors.As(err, &p) {
+			return KickOnError, protocol.Text(fmt.Sprintf("Bad packet id %d", p.ID))
+		}
+		return KickOnError, protocol.Text("Bad packet id")
+	case errors.Is(err, ErrBadState):
+		return KickOnError, protocol.Text("Unexpected packet")
+	}
+	return ContinueOnError, protocol.Chat{}
+}
+
+// ErrorCounts are the number of errors by type.
+type ErrorCounts struct {
+	UnknownPacket  uint64
+	PacketTooLarge uint64
+	BadState       uint64
+	Decode         uint64
+	// Other are the errors of the handlers that are not protocol
+	// violations.
+	Other uint64
+}
+
+// errorCounters counts errors concurrently.
+type errorCounters struct {
+	unknownPacket  atomic.Uint64
+	packetTooLarge atomic.Uint64
+	badState       atomic.Uint64
+	decode         atomic.Uint64
+	other          atomic.Uint64
+}
+
+func (e *errorCounters) add(err error) {
+	switch {
+	case errors.Is(err, ErrUnknownPacket):
+		e.unknownPacket.Add(1)
+	case errors.Is(err, ErrPacketTooLarge):
+		e.packetTooLarge.Add(1)
+	case errors.Is(err, ErrBadState):
+		e.badState.Add(1)
+	case errors.Is(err, ErrDecode):
+		e.decode.Add(1)
+	default:
+		e.other.Add(1)
+	}
+}
+
+func (e *errorCounters) counts() ErrorCounts {
+	return ErrorCounts{
+		UnknownPacket:  e.unknownPacket.Load(),
+		PacketTooLarge: e.packetTooLarge.Load(),
+		BadState:       e.badState.Load(),
+		Decode:         e.decode.Load(),
+		Other:          e.other.Load(),
+	}
+}
+
+// ErrorCounts returns the number of errors of the client by type.
+func (c *Client) ErrorCounts() ErrorCounts {
+	return c.errors.counts()
+}
+
+// ErrorCounts returns the number of errors of every client of the server by
+// type, including the disconnected ones.
+func (s *Server) ErrorCounts() ErrorCounts {
+	return s.errors.counts()
+}
+
+// handleError counts err and applies the ErrorPolicy of the client. It
+// returns whether the next packets should be handled.
+func (c *Client) handleError(err error) bool {
+	c.errors.add(err)
+	if c.server != nil {
+		c.server.errors.add(err)
+	}
+
+	policy := c.Config.ErrorPolicy
+	if policy == nil {
+		policy = DefaultErrorPolicy
+	}
+
+	logger := c.Logger()
+	var p *PacketError
+	if errors.As(err, &p) {
+		logger = logger.With("id", p.ID)
+	}
+
+	action, reason := policy(c, err)
+	switch action {
+	case KickOnError:
+		logger.Warn("kicking the client", "err", err, "reason", reason.String())
+		c.Disconnect(reason)
+		return false
+	case CloseOnError:
+		logger.Warn("closing the connection", "err", err)
+		c.conn.Close()
+		return false
+	}
+	logger.Warn("packet handler failed", "err", err)
+	return true
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/JDWardle/gocraft/protocol"
+	"github.com/JDWardle/gocraft/protocol/client"
+	"github.com/JDWardle/gocraft/protocol/server"
+)
+
+func TestDefaultErrorPolicy(t *testing.T) {
+	tests := map[error]ErrorAction{
+		&PacketError{Err: fmt.Errorf("%w: length 3000000", protocol.ErrPacketTooLarge)}: CloseOnError,
+		&PacketError{Err: fmt.Errorf("%w: EOF", ErrDecode)}:                             KickOnError,
+		&PacketError{ID: 0x7f, Err: ErrUnknownPacket}:                                   KickOnError,
+		fmt.Errorf("%w: keep alive ID 1", ErrBadState):                                  KickOnError,
+		errors.New("not implemented"):                                                   ContinueOnError,
+	}
+
+	for err, expected := range tests {
+		if action, _ := DefaultErrorPolicy(nil, err); action != expected {
+			t.Fatalf("Expected action %d for '%v' got %d", expected, err, action)
+		}
+	}
+}
+
+func TestUnknownPacket(t *testing.T) {
+	c := newTestClient(t, Config{CompressionThreshold: -1})
+	defer c.conn.Close()
+	c.write(int32(server.Handshake), &protocol.Handshake{
+		ProtocolVersion: protocol.DefaultVersion.Protocol,
+		NextState:       protocol.ClientStateLogin,
+	})
+	if err := c.w.WriteFrame(0x7f, nil); err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	disconnect := &protocol.LoginDisconnect{}
+	c.read(int32(client.LoginDisconnect), disconnect)
+	if reason := disconnect.Reason.String(); reason != "Bad packet id 127" {
+		t.Fatalf("Expected '%s' got '%s'", "Bad packet id 127", reason)
+	}
+
+	expected := ErrorCounts{UnknownPacket: 1}
+	if counts := c.client.ErrorCounts(); counts != expected {
+		t.Fatalf("Expected '%+v' got '%+v'", expected, counts)
+	}
+}
+
+func TestErrorPolicy(t *testing.T) {
+	var errs []error
+	c := newTestClient(t, Config{
+		CompressionThreshold: -1,
+		ErrorPolicy: func(c *Client, err error) (ErrorAction, protocol.Chat) {
+			errs = append(errs, err)
+			return ContinueOnError, protocol.Chat{}
+		},
+	})
+	defer c.conn.Close()
+	c.write(int32(server.Handshake), &protocol.Handshake{
+		ProtocolVersion: protocol.DefaultVersion.Protocol,
+		NextState:       protocol.ClientStateLogin,
+	})
+	// A truncated Login Start.
+	if err := c.w.WriteFrame(int32(server.LoginStart), []byte{0x05, 'N'}); err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	c.write(int32(server.LoginStart), &protocol.LoginStart{Name: "Notch"})
+	c.read(int32(client.LoginSuccess), &protocol.LoginSuccess{})
+
+	if len(errs) != 1 || !errors.Is(errs[0], ErrDecode) {
+		t.Fatalf("Expected a single '%v' got '%v'", ErrDecode, errs)
+	}
+	var packetErr *PacketError
+	if !errors.As(errs[0], &packetErr) || packetErr.ID != int32(server.LoginStart) || packetErr.State != protocol.ClientStateLogin {
+		t.Fatalf("Expected the error of Login Start got '%v'", errs[0])
+	}
+}
@@ -2,6 +2,8 @@ package server
 
 import (
 	"bufio"
+	"fmt"
+	"reflect"
 	"sync"
 
 	"github.com/JDWardle/gocraft/protocol"
@@ -9,82 +11,179 @@ import (
 
 type HandlerFunc func(c *Client, r *bufio.Reader) error
 
+// Middleware wraps the handler next of the packet id of state, for example to
+// log, measure or filter packets. It is called for each handled packet.
+type Middleware func(state protocol.ClientState, id int32, next HandlerFunc) HandlerFunc
+
+// Mux routes the packets of the clients to their handler by state and ID. The
+// IDs are the ones of protocol.DefaultVersion, the packets of the other
+// versions are routed to the packet with the same name, see
+// protocol.PacketName.
 type Mux struct {
-	m  map[protocol.ClientState]map[int32]HandlerFunc
-	mu sync.RWMutex
+	m          map[protocol.ClientState]map[int32]HandlerFunc
+	middleware []Middleware
+	mu         sync.RWMutex
+}
+
+// NewMux returns a Mux without handlers.
+func NewMux() *Mux {
+	return &Mux{m: make(map[protocol.ClientState]map[int32]HandlerFunc)}
+}
+
+// Handle registers h for the packet id of state, replacing the current
+// handler. A nil h removes the handler.
+func (m *Mux) Handle(state protocol.ClientState, id int32, h HandlerFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if h == nil {
+		delete(m.m[state], id)
+		return
+	}
+	if m.m[state] == nil {
+		m.m[state] = make(map[int32]HandlerFunc)
+	}
+	m.m[state][id] = h
+}
+
+// Use adds middleware to every handler of the mux, including the handlers
+// registered later. The first middleware added is the outermost.
+func (m *Mux) Use(middleware ...Middleware) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.middleware = append(m.middleware, middleware...)
+}
+
+// Clone returns a copy of the handlers and middleware of the mux, to be
+// changed without affecting m.
+func (m *Mux) Clone() *Mux {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	clone := NewMux()
+	for state, handlers := range m.m {
+		clone.m[state] = make(map[int32]HandlerFunc, len(handlers))
+		for id, h := range handlers {
+			clone.m[state][id] = h
+		}
+	}
+	clone.middleware = append([]Middleware(nil), m.middleware...)
+	return clone
 }
 
-func (m Mux) GetHandler(clientState protocol.ClientState, id int32) (bool, HandlerFunc) {
+// HandlePacket registers fn for the serverbound packet P of state. The
+// packets are decoded with the layout of the client's version before fn is
+// called and converted to P when it differs, see protocol.ConvertPacket. A
+// packet that cannot be decoded or is longer than its layout returns a
+// PacketError matching ErrDecode. It panics if no packet of state in
+// protocol.DefaultVersion has the name of P, see protocol.PacketName.
+func HandlePacket[P protocol.Handler](m *Mux, state protocol.ClientState, fn func(c *Client, p P) error) {
+	var zero P
+	name := protocol.PacketName(zero)
+	id, ok := protocol.ServerPackets.IDByName(state, name)
+	if !ok {
+		panic(fmt.Sprintf("server: %T is not a serverbound packet of state %d", zero, state))
+	}
+
+	m.Handle(state, id, func(c *Client, r *bufio.Reader) error {
+		versionID, ok := c.Version.ServerPackets.IDByName(state, name)
+		if !ok {
+			return &PacketError{State: state, ID: id, Err: fmt.Errorf("%w: no %s packet in %s", ErrUnknownPacket, name, c.Version)}
+		}
+		_, h := c.Version.ServerPackets.GetPacket(state, versionID)
+		if err := h.Decode(r); err != nil {
+			return &PacketError{State: state, ID: versionID, Packet: h, Err: fmt.Errorf("%w: %w", ErrDecode, err)}
+		}
+		if _, err := r.Peek(1); err == nil {
+			return &PacketError{State: state, ID: versionID, Packet: h, Err: fmt.Errorf("%w: packet is longer than expected", ErrDecode)}
+		}
+		c.trace("packet decoded", "id", versionID, "packet", fmt.Sprintf("%T %+v", h, h))
+
+		p, ok := h.(P)
+		if !ok {
+			p = reflect.New(reflect.TypeOf(zero).Elem()).Interface().(P)
+			protocol.ConvertPacket(p, h)
+		}
+		return fn(c, p)
+	})
+}
+
+// GetHandler returns the handler of the packet id of state wrapped in the
+// middleware.
+func (m *Mux) GetHandler(clientState protocol.ClientState, id int32) (bool, HandlerFunc) {
 	m.mu.RLock()
+	defer m.mu.RUnlock()
 
-	if h, ok := m.m[clientState][id]; ok {
-		m.mu.RUnlock()
-		return ok, h
+	h, ok := m.m[clientState][id]
+	if !ok {
+		return false, nil
 	}
 
-	m.mu.RUnlock()
-	return false, nil
+	for i := len(m.middleware) - 1; i >= 0; i-- {
+		h = m.middleware[i](clientState, id, h)
+	}
+	return true, h
 }
 
-var DefaultHandlers = &Mux{
-	m: map[protocol.ClientState]map[int32]HandlerFunc{
-		protocol.ClientStateHandshaking: {
-			0x00: HandshakeHandler,
-			0xFE: LegacyServerListPingHandler,
-		},
-		protocol.ClientStateStatus: {
-			0x00: StatusRequestHandler,
-			0x01: PingHandler,
-		},
-		protocol.ClientStateLogin: {
-			0x00: LoginStartHandler,
-			0x01: EncryptionResponseHandler,
-			0x02: LoginPluginResponseHandler,
-		},
-		protocol.ClientStatePlay: {
-			0x00: TeleportConfirmHandler,
-			0x01: QueryBlockNBTHandler,
-			0x02: ChatMessageHandler,
-			0x03: ClientStatusHandler,
-			0x04: ClientSettingsHandler,
-			0x05: TabCompleteHandler,
-			0x06: ConfirmTransactionHandler,
-			0x07: EnchantItemHandler,
-			0x08: ClickWindowHandler,
-			0x09: CloseWindowHandler,
-			0x0A: PluginMessageHandler,
-			0x0B: EditBookHandler,
-			0x0C: QueryEntityNBTHandler,
-			0x0D: UseEntityHandler,
-			0x0E: KeepAliveHandler,
-			0x0F: PlayerHandler,
-			0x10: PlayerPositionHandler,
-			0x11: PlayerPositionAndLookHandler,
-			0x12: PlayerLookHandler,
-			0x13: VehicleMoveHandler,
-			0x14: SteerBoatHandler,
-			0x15: PickItemHandler,
-			0x16: CraftRecipeRequestHandler,
-			0x17: PlayerAbilitiesHandler,
-			0x18: PlayerDiggingHandler,
-			0x19: EntityActionHandler,
-			0x1A: SteerVehicleHandler,
-			0x1B: RecipeBookDataHandler,
-			0x1C: NameItemHandler,
-			0x1D: ResourcePackStatusHandler,
-			0x1E: AdvancementTabHandler,
-			0x1F: SelectTradeHandler,
-			0x20: SetBeaconEffectHandler,
-			0x21: HeldItemChangeHandler,
-			0x22: UpdateCommandBlockHandler,
-			0x23: UpdateCommandBlockMinecartHandler,
-			0x24: CreativeInventoryActionHandler,
-			0x25: UpdateStructureBlockHandler,
-			0x26: UpdateSignHandler,
-			0x27: AnimationHandler,
-			0x28: SpectateHandler,
-			0x29: PlayerBlockPlacementHandler,
-			0x2A: UseItemHandler,
-		},
-	},
+// DefaultHandlers is the Mux of the clients unless set otherwise. Use Clone
+// to change the handlers of a Server only.
+var DefaultHandlers = newDefaultHandlers()
+
+func newDefaultHandlers() *Mux {
+	m := NewMux()
+	HandlePacket(m, protocol.ClientStateHandshaking, HandshakeHandler)
+	m.Handle(protocol.ClientStateHandshaking, 0xFE, LegacyServerListPingHandler)
+
+	HandlePacket(m, protocol.ClientStateStatus, StatusRequestHandler)
+	HandlePacket(m, protocol.ClientStateStatus, PingHandler)
+
+	HandlePacket(m, protocol.ClientStateLogin, LoginStartHandler)
+	HandlePacket(m, protocol.ClientStateLogin, EncryptionResponseHandler)
+	HandlePacket(m, protocol.ClientStateLogin, LoginPluginResponseHandler)
+
+	HandlePacket(m, protocol.ClientStatePlay, TeleportConfirmHandler)
+	HandlePacket(m, protocol.ClientStatePlay, QueryBlockNBTHandler)
+	HandlePacket(m, protocol.ClientStatePlay, ChatMessageHandler)
+	HandlePacket(m, protocol.ClientStatePlay, ClientStatusHandler)
+	HandlePacket(m, protocol.ClientStatePlay, ClientSettingsHandler)
+	HandlePacket(m, protocol.ClientStatePlay, TabCompleteHandler)
+	HandlePacket(m, protocol.ClientStatePlay, ConfirmTransactionHandler)
+	HandlePacket(m, protocol.ClientStatePlay, EnchantItemHandler)
+	HandlePacket(m, protocol.ClientStatePlay, ClickWindowHandler)
+	HandlePacket(m, protocol.ClientStatePlay, CloseWindowHandler)
+	HandlePacket(m, protocol.ClientStatePlay, PluginMessageHandler)
+	HandlePacket(m, protocol.ClientStatePlay, EditBookHandler)
+	HandlePacket(m, protocol.ClientStatePlay, QueryEntityNBTHandler)
+	HandlePacket(m, protocol.ClientStatePlay, UseEntityHandler)
+	HandlePacket(m, protocol.ClientStatePlay, KeepAliveHandler)
+	HandlePacket(m, protocol.ClientStatePlay, PlayerHandler)
+	HandlePacket(m, protocol.ClientStatePlay, PlayerPositionHandler)
+	HandlePacket(m, protocol.ClientStatePlay, PlayerPositionAndLookHandler)
+	HandlePacket(m, protocol.ClientStatePlay, PlayerLookHandler)
+	HandlePacket(m, protocol.ClientStatePlay, VehicleMoveHandler)
+	HandlePacket(m, protocol.ClientStatePlay, SteerBoatHandler)
+	HandlePacket(m, protocol.ClientStatePlay, PickItemHandler)
+	HandlePacket(m, protocol.ClientStatePlay, CraftRecipeRequestHandler)
+	HandlePacket(m, protocol.ClientStatePlay, PlayerAbilitiesHandler)
+	HandlePacket(m, protocol.ClientStatePlay, PlayerDiggingHandler)
+	HandlePacket(m, protocol.ClientStatePlay, EntityActionHandler)
+	HandlePacket(m, protocol.ClientStatePlay, SteerVehicleHandler)
+	HandlePacket(m, protocol.ClientStatePlay, RecipeBookDataHandler)
+	HandlePacket(m, protocol.ClientStatePlay, NameItemHandler)
+	HandlePacket(m, protocol.ClientStatePlay, ResourcePackStatusHandler)
+	HandlePacket(m, protocol.ClientStatePlay, AdvancementTabHandler)
+	HandlePacket(m, protocol.ClientStatePlay, SelectTradeHandler)
+	HandlePacket(m, protocol.ClientStatePlay, SetBeaconEffectHandler)
+	HandlePacket(m, protocol.ClientStatePlay, HeldItemChangeHandler)
+	HandlePacket(m, protocol.ClientStatePlay, UpdateCommandBlockHandler)
+	HandlePacket(m, protocol.ClientStatePlay, UpdateCommandBlockMinecartHandler)
+	HandlePacket(m, protocol.ClientStatePlay, CreativeInventoryActionHandler)
+	HandlePacket(m, protocol.ClientStatePlay, UpdateStructureBlockHandler)
+	HandlePacket(m, protocol.ClientStatePlay, UpdateSignHandler)
+	HandlePacket(m, protocol.ClientStatePlay, AnimationHandler)
+	HandlePacket(m, protocol.ClientStatePlay, SpectateHandler)
+	HandlePacket(m, protocol.ClientStatePlay, PlayerBlockPlacementHandler)
+	HandlePacket(m, protocol.ClientStatePlay, UseItemHandler)
+	return m
 }
@@ -0,0 +1,188 @@
+package server
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/JDWardle/gocraft/protocol"
+	"github.com/JDWardle/gocraft/protocol/server"
+)
+
+func TestMux(t *testing.T) {
+	var calls []string
+	handler := func(name string) HandlerFunc {
+		return func(c *Client, r *bufio.Reader) error {
+			calls = append(calls, name)
+			return nil
+		}
+	}
+	middleware := func(name string) Middleware {
+		return func(state protocol.ClientState, id int32, next HandlerFunc) HandlerFunc {
+			return func(c *Client, r *bufio.Reader) error {
+				calls = append(calls, name)
+				return next(c, r)
+			}
+		}
+	}
+
+	m := NewMux()
+	m.Use(middleware("first"))
+	m.Handle(protocol.ClientStatePlay, 0x02, handler("chat"))
+	m.Use(middleware("second"))
+
+	clone := m.Clone()
+	clone.Handle(protocol.ClientStatePlay, 0x02, handler("clone"))
+	clone.Handle(protocol.ClientStateStatus, 0x00, handler("status"))
+
+	tests := map[string]struct {
+		mux   *Mux
+		state protocol.ClientState
+		id    int32
+	}{
+		"first,second,chat":  {m, protocol.ClientStatePlay, 0x02},
+		"first,second,clone": {clone, protocol.ClientStatePlay, 0x02},
+		"":                   {m, protocol.ClientStateStatus, 0x00},
+	}
+	for expected, test := range tests {
+		calls = nil
+		ok, h := test.mux.GetHandler(test.state, test.id)
+		if ok != (expected != "") {
+			t.Fatalf("Expected a handler for %#02x: %t got %t", test.id, expected != "", ok)
+		}
+		if ok {
+			h(nil, nil)
+		}
+		if got := strings.Join(calls, ","); got != expected {
+			t.Fatalf("Expected '%s' got '%s'", expected, got)
+		}
+	}
+
+	m.Handle(protocol.ClientStatePlay, 0x02, nil)
+	if ok, _ := m.GetHandler(protocol.ClientStatePlay, 0x02); ok {
+		t.Fatalf("Expected the handler to be removed")
+	}
+}
+
+func TestHandlePacket(t *testing.T) {
+	var got *protocol.ChatMessage
+	m := NewMux()
+	HandlePacket(m, protocol.ClientStatePlay, func(c *Client, p *protocol.ChatMessage) error {
+		got = p
+		return nil
+	})
+
+	ok, h := m.GetHandler(protocol.ClientStatePlay, int32(server.ChatMessage))
+	if !ok {
+		t.Fatalf("Expected a handler for Chat Message")
+	}
+
+	tests := map[string]bool{
+		"\x02hi":   true,
+		"\x02h":    false,
+		"\x02hi!":  false,
+		"\x80\x80": false,
+	}
+	for data, valid := range tests {
+		got = nil
+		err := h(&Client{Version: protocol.DefaultVersion}, bufio.NewReader(strings.NewReader(data)))
+		if valid {
+			if err != nil || got == nil || got.Message != "hi" {
+				t.Fatalf("Expected '%q' to be handled got '%+v' and '%v'", data, got, err)
+			}
+			continue
+		}
+		if !errors.Is(err, ErrDecode) || got != nil {
+			t.Fatalf("Expected '%v' for '%q' got '%v'", ErrDecode, data, err)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("Expected HandlePacket to panic for a packet of another state")
+		}
+	}()
+	HandlePacket(m, protocol.ClientStateLogin, func(c *Client, p *protocol.ChatMessage) error { return nil })
+}
+
+// ChatMessage340 is a Chat Message with another layout, as generated for an
+// older version.
+type ChatMessage340 struct {
+	Message  string `mc:"string,max=100"`
+	Position int8
+}
+
+func (p *ChatMessage340) Decode(r *bufio.Reader) error {
+	return protocol.Unmarshal(r, p)
+}
+
+func (p *ChatMessage340) Encode() ([]byte, error) {
+	return protocol.Marshal(p)
+}
+
+// ClientboundKeepAlive340 is a Keep Alive with another layout.
+type ClientboundKeepAlive340 struct {
+	KeepAliveID int32 `mc:"varint"`
+}
+
+func (p *ClientboundKeepAlive340) Decode(r *bufio.Reader) error {
+	return protocol.Unmarshal(r, p)
+}
+
+func (p *ClientboundKeepAlive340) Encode() ([]byte, error) {
+	return protocol.Marshal(p)
+}
+
+// testVersion is a version whose Chat Message and Keep Alive have another ID
+// and layout.
+var testVersion = &protocol.Version{
+	Protocol: 340,
+	Name:     "1.12.2",
+	ServerPackets: protocol.NewPackets(map[protocol.ClientState]map[int32]protocol.Handler{
+		protocol.ClientStatePlay: {
+			0x01: &ChatMessage340{},
+			0x0B: &protocol.KeepAlive{},
+		},
+	}),
+	ClientPackets: protocol.NewPackets(map[protocol.ClientState]map[int32]protocol.Handler{
+		protocol.ClientStatePlay: {
+			0x1F: &ClientboundKeepAlive340{},
+		},
+	}),
+}
+
+func TestHandlePacketVersion(t *testing.T) {
+	messages := make(chan *protocol.ChatMessage, 1)
+	raw := make(chan *ChatMessage340, 1)
+	m := NewMux()
+	HandlePacket(m, protocol.ClientStatePlay, func(c *Client, p *protocol.ChatMessage) error {
+		messages <- p
+		return nil
+	})
+
+	conn, serverConn := net.Pipe()
+	defer conn.Close()
+	c := NewClient(1, serverConn)
+	c.Version = testVersion
+	c.State = protocol.ClientStatePlay
+	c.Handlers = m
+	go c.HandleMessages()
+
+	tc := wrapTestClient(t, conn)
+	tc.write(0x01, &ChatMessage340{Message: "hi", Position: 1})
+	if p := <-messages; p.Message != "hi" {
+		t.Fatalf("Expected '%s' got '%s'", "hi", p.Message)
+	}
+
+	// A handler of the layout of the version gets the packet as is.
+	HandlePacket(m, protocol.ClientStatePlay, func(c *Client, p *ChatMessage340) error {
+		raw <- p
+		return nil
+	})
+	tc.write(0x01, &ChatMessage340{Message: "hi", Position: 1})
+	if p := <-raw; *p != (ChatMessage340{Message: "hi", Position: 1}) {
+		t.Fatalf("Expected '%+v' got '%+v'", ChatMessage340{Message: "hi", Position: 1}, *p)
+	}
+}
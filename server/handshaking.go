@@ -2,30 +2,162 @@ package server
 
 import (
 	"bufio"
-	"errors"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
 
 	"github.com/JDWardle/gocraft/protocol"
 )
 
-func HandshakeHandler(c *Client, r *bufio.Reader) error {
-	h := &protocol.Handshake{}
-	err := h.Decode(r)
-	if err != nil {
-		return err
+func HandshakeHandler(c *Client, h *protocol.Handshake) error {
+	// Play is only reached by logging in. The ErrorPolicy closes the
+	// connection, there is no Disconnect packet in this state.
+	if h.NextState != protocol.ClientStateStatus && h.NextState != protocol.ClientStateLogin {
+		return fmt.Errorf("%w: next state %d", ErrBadState, h.NextState)
 	}
 
-	c.State = h.NextState
+	c.handshake = *h
 
-	if h.NextState == protocol.ClientStateStatus {
-		c.State = h.NextState
-		packet := append(protocol.VarInt(0), protocol.String(`{"version":{"name":"1.13.2","protocol":404},"players":{"max":100000000,"online":0,"sample":[]},"description":{"text":"Hello Minecraft from Go!"}}`)...)
-		packet = append(protocol.VarInt(int32(len(packet))), packet...)
-		c.conn.Write(packet)
+	version, supported := protocol.SupportedVersions.Get(h.ProtocolVersion)
+
+	// Send reads the version from other goroutines.
+	c.mu.Lock()
+	if supported {
+		c.Version = version
 	}
+	c.State = h.NextState
+	c.mu.Unlock()
+	c.setLogFields(h.NextState, "")
 
+	if h.NextState == protocol.ClientStateLogin && !supported {
+		return c.rejectVersion(h.ProtocolVersion)
+	}
 	return nil
 }
 
+// rejectVersion disconnects a client logging in with an unsupported protocol
+// version, telling it which versions are supported.
+func (c *Client) rejectVersion(protocolVersion int32) error {
+	versions := protocol.SupportedVersions.List()
+	reason := fmt.Sprintf("Unsupported protocol version %d! This server supports %s", protocolVersion, protocol.SupportedVersions.Range())
+	switch {
+	case len(versions) == 0:
+	case protocolVersion < versions[0].Protocol:
+		reason = fmt.Sprintf("Outdated client! Please use %s", protocol.SupportedVersions.Range())
+	case protocolVersion > versions[len(versions)-1].Protocol:
+		reason = fmt.Sprintf("Outdated server! I'm still on %s", protocol.SupportedVersions.Range())
+	}
+
+	return c.Disconnect(protocol.Text(reason))
+}
+
+// legacyProtocol is the protocol version sent in legacy ping responses. It is
+// higher than the versions of legacy clients so they show the server as
+// incompatible.
+const legacyProtocol = 127
+
+// LegacyServerListPingHandler answers the server list ping of clients older
+// than 1.7, which is not length prefixed and starts with 0xFE. 1.4 and 1.5
+// follow it with 0x01, 1.6 also sends a MC|PingHost plugin message.
+// See https://wiki.vg/Server_List_Ping#1.6 for more info.
 func LegacyServerListPingHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+	if _, err := r.ReadByte(); err != nil {
+		return err
+	}
+
+	// Older clients only send 0xFE so reading more would block forever.
+	legacy := r.Buffered() > 0
+	if legacy {
+		b, err := r.ReadByte()
+		if err != nil {
+			return err
+		}
+		if b != 0x01 {
+			return fmt.Errorf("invalid legacy ping payload %#02x", b)
+		}
+
+		if r.Buffered() > 0 {
+			if b, err := r.Peek(1); err == nil && b[0] == 0xFA {
+				if err := c.readPingHost(r); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	s, err := c.status()
+	if err != nil {
+		return err
+	}
+
+	var response string
+	if legacy {
+		response = strings.Join([]string{
+			"§1",
+			strconv.Itoa(legacyProtocol),
+			s.Version.Name,
+			s.Description.Legacy(),
+			strconv.Itoa(s.Players.Online),
+			strconv.Itoa(s.Players.Max),
+		}, "\x00")
+	} else {
+		// The fields are separated by § so the MOTD must not contain any.
+		response = strings.Join([]string{
+			strings.Replace(s.Description.String(), "§", "", -1),
+			strconv.Itoa(s.Players.Online),
+			strconv.Itoa(s.Players.Max),
+		}, "§")
+	}
+
+	// The response is a Kick packet.
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.enqueue(outbound{raw: append([]byte{0xFF}, protocol.LegacyString(response)...)})
+}
+
+// readPingHost reads the MC|PingHost plugin message sent by 1.6 clients with
+// the address they connected to.
+func (c *Client) readPingHost(r *bufio.Reader) error {
+	if _, err := r.ReadByte(); err != nil {
+		return err
+	}
+
+	channel, err := protocol.ReadLegacyString(r, 32)
+	if err != nil {
+		return err
+	}
+	if channel != "MC|PingHost" {
+		return fmt.Errorf("unexpected legacy plugin message channel %q", channel)
+	}
+
+	var length uint16
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return err
+	}
+
+	var ping struct {
+		ProtocolVersion uint8
+		Host            string
+		Port            int32
+	}
+	data := io.LimitReader(r, int64(length))
+	if err := binary.Read(data, binary.BigEndian, &ping.ProtocolVersion); err != nil {
+		return err
+	}
+	if ping.Host, err = protocol.ReadLegacyString(data, 255); err != nil {
+		return err
+	}
+	if err := binary.Read(data, binary.BigEndian, &ping.Port); err != nil {
+		return err
+	}
+
+	c.handshake = protocol.Handshake{
+		ProtocolVersion: int32(ping.ProtocolVersion),
+		ServerAddress:   ping.Host,
+		ServerPort:      uint16(ping.Port),
+	}
+	return nil
 }
@@ -0,0 +1,36 @@
+package server
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/JDWardle/gocraft/protocol"
+	"github.com/JDWardle/gocraft/protocol/server"
+)
+
+func TestHandshakeNextState(t *testing.T) {
+	errs := make(chan error, 1)
+	c := newTestClient(t, Config{
+		CompressionThreshold: -1,
+		ErrorPolicy: func(c *Client, err error) (ErrorAction, protocol.Chat) {
+			errs <- err
+			return DefaultErrorPolicy(c, err)
+		},
+	})
+	defer c.conn.Close()
+	c.write(int32(server.Handshake), &protocol.Handshake{
+		ProtocolVersion: protocol.DefaultVersion.Protocol,
+		NextState:       protocol.ClientStatePlay,
+	})
+
+	if err := <-errs; !errors.Is(err, ErrBadState) {
+		t.Fatalf("Expected '%v' got '%v'", ErrBadState, err)
+	}
+	// The connection is closed without a packet.
+	if frame, err := c.r.ReadFrame(); err == nil {
+		t.Fatalf("Expected the connection to be closed got packet %#02x", frame.ID)
+	}
+	if state := c.client.State; state != protocol.ClientStateHandshaking {
+		t.Fatalf("Expected state %d got %d", protocol.ClientStateHandshaking, state)
+	}
+}
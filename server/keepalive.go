@@ -0,0 +1,111 @@
+package server
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/JDWardle/gocraft/protocol"
+)
+
+const (
+	// DefaultKeepAliveInterval is the interval at which Keep Alive is sent
+	// when the Config has no KeepAliveInterval, as done by vanilla servers.
+	DefaultKeepAliveInterval = 15 * time.Second
+	// DefaultKeepAliveTimeout is the time given to the players to answer a
+	// Keep Alive when the Config has no KeepAliveTimeout.
+	DefaultKeepAliveTimeout = 30 * time.Second
+	// DefaultLoginTimeout is the time given to the clients to log in when
+	// the Config has no LoginTimeout.
+	DefaultLoginTimeout = 30 * time.Second
+)
+
+// timeoutReason is the reason of the players disconnected for not answering
+// a Keep Alive, shown as "Timed out" by the client.
+var timeoutReason = protocol.Translate("disconnect.timeout")
+
+// keepAlive is the state of the Keep Alive exchange of a player.
+type keepAlive struct {
+	// pending is set while the Keep Alive of ID id, sent at sent, waits for
+	// an answer.
+	pending bool
+	id      int64
+	sent    time.Time
+
+	latency time.Duration
+}
+
+// Latency returns the round trip time to the client measured with Keep
+// Alive, as shown in the player list. It is 0 until the first answer.
+func (c *Client) Latency() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.keepAlive.latency
+}
+
+// startKeepAlive sends Keep Alive to the player every
+// Config.KeepAliveInterval until the client is closed, disconnecting it once
+// an answer takes longer than Config.KeepAliveTimeout.
+func (c *Client) startKeepAlive() {
+	interval := c.Config.keepAliveInterval()
+	timeout := c.Config.keepAliveTimeout()
+
+	go func() {
+		// Sent on their own ticker, comparing the jittered ticks to the
+		// last Keep Alive would skip whole intervals.
+		send := time.NewTicker(interval)
+		defer send.Stop()
+		// The deadline is checked twice per timeout.
+		check := time.NewTicker(timeout / 2)
+		defer check.Stop()
+
+		for {
+			var now time.Time
+			sending := false
+			select {
+			case <-c.closed:
+				return
+			case now = <-send.C:
+				sending = true
+			case now = <-check.C:
+			}
+
+			c.mu.Lock()
+			k := &c.keepAlive
+			if k.pending && now.Sub(k.sent) >= timeout {
+				c.mu.Unlock()
+				c.Logger().Info("keep alive timed out", "timeout", timeout)
+				c.Disconnect(timeoutReason)
+				return
+			}
+
+			var err error
+			if sending && !k.pending {
+				k.pending, k.id, k.sent = true, rand.Int63(), now
+				err = c.send(&protocol.ClientboundKeepAlive{KeepAliveID: k.id})
+			}
+			c.mu.Unlock()
+			if err != nil {
+				c.Logger().Warn("keep alive failed", "err", err)
+				return
+			}
+		}
+	}()
+}
+
+func KeepAliveHandler(c *Client, keepAlive *protocol.KeepAlive) error {
+	c.mu.Lock()
+	k := &c.keepAlive
+	if !k.pending || keepAlive.KeepAliveID != k.id {
+		c.mu.Unlock()
+		// The ErrorPolicy disconnects the player.
+		return fmt.Errorf("%w: keep alive ID %d", ErrBadState, keepAlive.KeepAliveID)
+	}
+
+	k.pending = false
+	// Smoothed like vanilla servers do.
+	k.latency = (k.latency*3 + time.Since(k.sent)) / 4
+	c.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/JDWardle/gocraft/protocol"
+	"github.com/JDWardle/gocraft/protocol/client"
+	"github.com/JDWardle/gocraft/protocol/server"
+)
+
+func TestKeepAlive(t *testing.T) {
+	c := newTestClient(t, Config{
+		CompressionThreshold: -1,
+		FlushInterval:        time.Millisecond,
+		KeepAliveInterval:    10 * time.Millisecond,
+		KeepAliveTimeout:     100 * time.Millisecond,
+	})
+	defer c.conn.Close()
+	c.login("Notch")
+	c.read(int32(client.LoginSuccess), &protocol.LoginSuccess{})
+	c.read(int32(client.JoinGame), &protocol.JoinGame{})
+	c.read(int32(client.SpawnPosition), &protocol.SpawnPosition{})
+	c.read(int32(client.PlayerPositionAndLook), &protocol.ClientboundPlayerPositionAndLook{})
+
+	first := &protocol.ClientboundKeepAlive{}
+	c.read(int32(client.KeepAlive), first)
+	time.Sleep(20 * time.Millisecond)
+	c.write(int32(server.KeepAlive), &protocol.KeepAlive{KeepAliveID: first.KeepAliveID})
+
+	second := &protocol.ClientboundKeepAlive{}
+	c.read(int32(client.KeepAlive), second)
+	if second.KeepAliveID == first.KeepAliveID {
+		t.Fatalf("Expected a new keep alive ID got %d again", second.KeepAliveID)
+	}
+	// The latency is smoothed, a quarter of the first round trip counts.
+	if latency := c.client.Latency(); latency < 5*time.Millisecond {
+		t.Fatalf("Expected a latency of at least 5ms got '%s'", latency)
+	}
+
+	// The player is disconnected once it stops answering.
+	disconnect := &protocol.Disconnect{}
+	c.read(int32(client.Disconnect), disconnect)
+	if !reflect.DeepEqual(disconnect.Reason, timeoutReason) {
+		t.Fatalf("Expected '%+v' got '%+v'", timeoutReason, disconnect.Reason)
+	}
+}
+
+func TestKeepAliveWrongID(t *testing.T) {
+	c := newTestClient(t, Config{CompressionThreshold: -1, FlushInterval: time.Millisecond})
+	defer c.conn.Close()
+	c.login("Notch")
+	c.read(int32(client.LoginSuccess), &protocol.LoginSuccess{})
+	c.read(int32(client.JoinGame), &protocol.JoinGame{})
+	c.read(int32(client.SpawnPosition), &protocol.SpawnPosition{})
+	c.read(int32(client.PlayerPositionAndLook), &protocol.ClientboundPlayerPositionAndLook{})
+
+	// No Keep Alive was sent yet.
+	c.write(int32(server.KeepAlive), &protocol.KeepAlive{KeepAliveID: 1})
+
+	// The player is disconnected once.
+	disconnect := &protocol.Disconnect{}
+	c.read(int32(client.Disconnect), disconnect)
+	if reason := disconnect.Reason.String(); reason != "Unexpected packet" {
+		t.Fatalf("Expected '%s' got '%s'", "Unexpected packet", reason)
+	}
+	if frame, err := c.r.ReadFrame(); err == nil {
+		t.Fatalf("Expected the connection to be closed got packet %#02x", frame.ID)
+	}
+}
+
+func TestKeepAliveInterval(t *testing.T) {
+	const interval = 50 * time.Millisecond
+	c := newTestClient(t, Config{
+		CompressionThreshold: -1,
+		FlushInterval:        time.Millisecond,
+		KeepAliveInterval:    interval,
+		KeepAliveTimeout:     time.Second,
+	})
+	defer c.conn.Close()
+	c.login("Notch")
+	c.read(int32(client.LoginSuccess), &protocol.LoginSuccess{})
+	c.read(int32(client.JoinGame), &protocol.JoinGame{})
+	c.read(int32(client.SpawnPosition), &protocol.SpawnPosition{})
+	c.read(int32(client.PlayerPositionAndLook), &protocol.ClientboundPlayerPositionAndLook{})
+
+	// A player answering right away gets a Keep Alive every interval, none
+	// is skipped.
+	var last time.Time
+	for i := 0; i < 6; i++ {
+		keepAlive := &protocol.ClientboundKeepAlive{}
+		c.read(int32(client.KeepAlive), keepAlive)
+		now := time.Now()
+		if gap := now.Sub(last); i > 0 && gap > interval*3/2 {
+			t.Fatalf("Expected a keep alive every %s got a gap of %s", interval, gap)
+		}
+		last = now
+		c.write(int32(server.KeepAlive), &protocol.KeepAlive{KeepAliveID: keepAlive.KeepAliveID})
+	}
+}
+
+func TestLoginTimeout(t *testing.T) {
+	c := newTestClient(t, Config{LoginTimeout: 10 * time.Millisecond})
+	defer c.conn.Close()
+
+	c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := c.r.ReadFrame(); err == nil || isTimeout(err) {
+		t.Fatalf("Expected the connection to be closed got '%v'", err)
+	}
+}
+
+func isTimeout(err error) bool {
+	ne, ok := err.(interface{ Timeout() bool })
+	return ok && ne.Timeout()
+}
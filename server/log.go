@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	"github.com/JDWardle/gocraft/protocol"
+)
+
+// LevelTrace is the level of the packets logged for the clients in trace
+// mode, see Client.SetTrace.
+const LevelTrace = slog.LevelDebug - 4
+
+// LogFormat is the output format of a logger returned by NewLogger.
+type LogFormat int
+
+const (
+	// LogConsole writes key=value pairs, one record per line.
+	LogConsole LogFormat = iota
+	// LogJSON writes a JSON object per line.
+	LogJSON
+)
+
+// NewLogger returns a logger writing the records of at least level to w in
+// format. The records of the clients in trace mode are written whatever the
+// level.
+func NewLogger(w io.Writer, format LogFormat, level slog.Leveler) *slog.Logger {
+	opts := &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.LevelKey && len(groups) == 0 {
+				if l, ok := a.Value.Any().(slog.Level); ok && l == LevelTrace {
+					a.Value = slog.StringValue("TRACE")
+				}
+			}
+			return a
+		},
+	}
+
+	if format == LogJSON {
+		return slog.New(slog.NewJSONHandler(w, opts))
+	}
+	return slog.New(slog.NewTextHandler(w, opts))
+}
+
+// traceHandler enables the LevelTrace records of a handler with a higher
+// level.
+type traceHandler struct {
+	slog.Handler
+}
+
+func (h traceHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level == LevelTrace || h.Handler.Enabled(ctx, level)
+}
+
+func (h traceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return traceHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h traceHandler) WithGroup(name string) slog.Handler {
+	return traceHandler{h.Handler.WithGroup(name)}
+}
+
+// SetTrace enables or disables the trace mode of the client, logging every
+// packet received and sent at LevelTrace for protocol debugging.
+func (c *Client) SetTrace(trace bool) {
+	c.tracing.Store(trace)
+}
+
+// Logger returns the logger of the client with its ID, remote address, state
+// and username, once known, as fields. It is safe to call from any goroutine.
+func (c *Client) Logger() *slog.Logger {
+	logger := c.Config.logger()
+	if c.tracing.Load() {
+		logger = slog.New(traceHandler{logger.Handler()})
+	}
+
+	c.logMu.Lock()
+	state, username := c.logState, c.logUsername
+	c.logMu.Unlock()
+
+	args := []interface{}{"client", c.ID}
+	if c.conn != nil {
+		args = append(args, "remote", c.conn.RemoteAddr().String())
+	}
+	args = append(args, "state", int(state))
+	if username != "" {
+		args = append(args, "username", username)
+	}
+	return logger.With(args...)
+}
+
+// trace logs msg at LevelTrace when the client is in trace mode or the
+// logger enables it.
+func (c *Client) trace(msg string, args ...interface{}) {
+	if !c.tracing.Load() && !c.Config.logger().Enabled(context.Background(), LevelTrace) {
+		return
+	}
+	c.Logger().Log(context.Background(), LevelTrace, msg, args...)
+}
+
+// setLogFields updates the fields of the logger of the client.
+func (c *Client) setLogFields(state protocol.ClientState, username string) {
+	c.logMu.Lock()
+	defer c.logMu.Unlock()
+
+	c.logState = state
+	if username != "" {
+		c.logUsername = username
+	}
+}
+
+func (c *Config) logger() *slog.Logger {
+	if c.Logger == nil {
+		return slog.Default()
+	}
+	return c.Logger
+}
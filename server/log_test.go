@@ -0,0 +1,55 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/JDWardle/gocraft/protocol"
+)
+
+func TestLogger(t *testing.T) {
+	var b bytes.Buffer
+	c := &Client{ID: 7, Config: Config{Logger: NewLogger(&b, LogJSON, slog.LevelInfo)}}
+	c.setLogFields(protocol.ClientStateLogin, "Notch")
+
+	c.Logger().Info("hello")
+	c.trace("dropped")
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(b.Bytes(), &record); err != nil {
+		t.Fatalf("Unexpected error decoding '%s': '%v'", b.String(), err)
+	}
+	expected := map[string]interface{}{
+		"level":    "INFO",
+		"msg":      "hello",
+		"client":   float64(7),
+		"state":    float64(protocol.ClientStateLogin),
+		"username": "Notch",
+	}
+	for key, value := range expected {
+		if record[key] != value {
+			t.Fatalf("Expected '%s' to be '%v' got '%v'", key, value, record[key])
+		}
+	}
+
+	b.Reset()
+	c.SetTrace(true)
+	c.trace("packet received", "id", 0x02)
+	if s := b.String(); !strings.Contains(s, `"level":"TRACE"`) || !strings.Contains(s, `"id":2`) {
+		t.Fatalf("Expected a trace record got '%s'", s)
+	}
+}
+
+func TestConsoleLogger(t *testing.T) {
+	var b bytes.Buffer
+	c := &Client{ID: 1, Config: Config{Logger: NewLogger(&b, LogConsole, slog.LevelWarn)}}
+
+	c.Logger().Info("dropped")
+	c.Logger().Warn("kept", "id", 5)
+	if s := b.String(); strings.Contains(s, "dropped") || !strings.Contains(s, "level=WARN msg=kept client=1 state=0 id=5") {
+		t.Fatalf("Expected only the warning got '%s'", s)
+	}
+}
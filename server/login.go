@@ -1,26 +1,182 @@
 package server
 
 import (
-	"bufio"
-	"errors"
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
 	"fmt"
+	"time"
 
+	"github.com/JDWardle/gocraft/auth"
 	"github.com/JDWardle/gocraft/protocol"
 )
 
-func LoginStartHandler(c *Client, r *bufio.Reader) error {
-	s, err := protocol.ReadString(r)
+func LoginStartHandler(c *Client, p *protocol.LoginStart) error {
+	if c.loginName != "" {
+		return fmt.Errorf("%w: login start of %q after %q", ErrBadState, p.Name, c.loginName)
+	}
+	c.loginName = p.Name
+	c.setLogFields(c.State, p.Name)
+	c.Logger().Info("login started")
+
+	if err := c.startLoginPlugins(); err != nil {
+		return err
+	}
+	if !c.pluginsDone() {
+		return nil
+	}
+	return c.authenticate()
+}
+
+// authenticate goes on with the login once the login plugins are done. Only
+// the first call does something.
+func (c *Client) authenticate() error {
+	c.mu.Lock()
+	started := c.authenticating
+	c.authenticating = true
+	c.mu.Unlock()
+	if started {
+		return nil
+	}
+
+	switch {
+	case c.Profile != nil:
+		// A login plugin authenticated the player.
+		return c.finishLogin()
+	case c.Config.OnlineMode:
+		return c.requestEncryption()
+	case !auth.ValidUsername(c.loginName):
+		return c.Disconnect(protocol.Text("Invalid username!"))
+	}
+
+	c.Profile = auth.OfflineProfile(c.loginName)
+	return c.finishLogin()
+}
+
+// requestEncryption sends the public key of the server and a random verify
+// token the client has to encrypt with it.
+func (c *Client) requestEncryption() error {
+	key, err := c.Config.privateKey()
 	if err != nil {
 		return err
 	}
-	fmt.Println(s)
-	return errors.New("not implemented")
+
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return err
+	}
+
+	c.verifyToken = make([]byte, 4)
+	if _, err := rand.Read(c.verifyToken); err != nil {
+		return err
+	}
+
+	return c.Send(&protocol.EncryptionRequest{
+		PublicKey:   publicKey,
+		VerifyToken: c.verifyToken,
+	})
 }
 
-func EncryptionResponseHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func EncryptionResponseHandler(c *Client, p *protocol.EncryptionResponse) error {
+	if c.verifyToken == nil {
+		return fmt.Errorf("%w: encryption response without request", ErrBadState)
+	}
+
+	key, err := c.Config.privateKey()
+	if err != nil {
+		return err
+	}
+
+	// The login cannot go on after these errors, the client is already
+	// encrypting its packets.
+	token, err := rsa.DecryptPKCS1v15(rand.Reader, key, p.VerifyToken)
+	if err != nil || !bytes.Equal(token, c.verifyToken) {
+		return fmt.Errorf("%w: invalid verify token", ErrBadState)
+	}
+	c.verifyToken = nil
+
+	secret, err := rsa.DecryptPKCS1v15(rand.Reader, key, p.SharedSecret)
+	if err != nil {
+		return fmt.Errorf("%w: invalid shared secret: %w", ErrDecode, err)
+	}
+
+	if err := c.enableEncryption(secret); err != nil {
+		return err
+	}
+
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return err
+	}
+
+	profile, err := c.Config.sessionVerifier().HasJoined(c.ctx, c.loginName, auth.ServerHash("", secret, publicKey))
+	if err != nil {
+		reason := "Failed to verify username!"
+		if err != auth.ErrNotJoined {
+			reason = "Authentication servers are down. Please try again later, sorry!"
+		}
+		c.Disconnect(protocol.Text(reason))
+		return err
+	}
+	c.Profile = profile
+
+	return c.finishLogin()
+}
+
+// finishLogin completes the login of the client once it is authenticated and
+// makes the player join the game.
+func (c *Client) finishLogin() error {
+	if err := c.SetCompression(c.Config.compressionThreshold()); err != nil {
+		return err
+	}
+
+	if c.server != nil {
+		if old := c.server.addPlayer(c); old != nil {
+			old.Disconnect(protocol.Text("You logged in from another location"))
+		}
+	}
+
+	err := c.Send(&protocol.LoginSuccess{
+		UUID:     c.Profile.ID.String(),
+		Username: c.Profile.Name,
+	})
+	if err != nil {
+		return err
+	}
+
+	c.setLogFields(c.State, c.Profile.Name)
+	c.setState(protocol.ClientStatePlay)
+	c.Logger().Info("player joined", "uuid", c.Profile.ID.String())
+	c.conn.SetReadDeadline(time.Time{})
+	c.startKeepAlive()
+	return c.join()
 }
 
-func LoginPluginResponseHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+// join spawns the player in the world. The client shows the world once it
+// receives the position of the player.
+func (c *Client) join() error {
+	err := c.Send(&protocol.JoinGame{
+		EntityID:   int32(c.ID),
+		Gamemode:   0, // Survival
+		Dimension:  0, // Overworld
+		Difficulty: 0, // Peaceful
+		// MaxPlayers is ignored by the client.
+		LevelType: "default",
+	})
+	if err != nil {
+		return err
+	}
+
+	spawn := protocol.Position{X: 0, Y: 64, Z: 0}
+	if err := c.Send(&protocol.SpawnPosition{Location: spawn}); err != nil {
+		return err
+	}
+
+	return c.Send(&protocol.ClientboundPlayerPositionAndLook{
+		X:          float64(spawn.X) + 0.5,
+		Y:          float64(spawn.Y),
+		Z:          float64(spawn.Z) + 0.5,
+		TeleportID: 1,
+	})
 }
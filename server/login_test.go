@@ -0,0 +1,264 @@
+package server
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/JDWardle/gocraft/auth"
+	"github.com/JDWardle/gocraft/auth/authtest"
+	"github.com/JDWardle/gocraft/protocol"
+	"github.com/JDWardle/gocraft/protocol/client"
+	"github.com/JDWardle/gocraft/protocol/server"
+	"github.com/gofrs/uuid"
+)
+
+// testClient is a scripted client connected to a Client of the server.
+type testClient struct {
+	t    *testing.T
+	conn net.Conn
+	r    *protocol.FrameReader
+	w    *protocol.FrameWriter
+	// client is the Client of the server, nil when the connection is accepted
+	// by a Server.
+	client *Client
+}
+
+func newTestClient(t *testing.T, config Config) *testClient {
+	conn, serverConn := net.Pipe()
+
+	c := NewClient(1, serverConn)
+	c.Config = config
+	go c.HandleMessages()
+
+	tc := wrapTestClient(t, conn)
+	tc.client = c
+	return tc
+}
+
+// wrapTestClient returns a testClient talking to the server over conn.
+func wrapTestClient(t *testing.T, conn net.Conn) *testClient {
+	return &testClient{
+		t:    t,
+		conn: conn,
+		r:    protocol.NewFrameReader(conn),
+		w:    protocol.NewFrameWriter(conn),
+	}
+}
+
+func (c *testClient) write(id int32, h protocol.Handler) {
+	data, err := h.Encode()
+	if err != nil {
+		c.t.Fatalf("Unexpected error encoding '%T': '%v'", h, err)
+	}
+	if err := c.w.WriteFrame(id, data); err != nil {
+		c.t.Fatalf("Unexpected error writing '%T': '%v'", h, err)
+	}
+}
+
+// read reads the next packet which must have the given ID and decodes it into
+// h.
+func (c *testClient) read(id int32, h protocol.Handler) {
+	frame, err := c.r.ReadFrame()
+	if err != nil {
+		c.t.Fatalf("Unexpected error reading '%T': '%v'", h, err)
+	}
+	if frame.ID != id {
+		c.t.Fatalf("Expected packet %#02x got %#02x", id, frame.ID)
+	}
+	if err := h.Decode(frame.Reader()); err != nil {
+		c.t.Fatalf("Unexpected error decoding '%T': '%v'", h, err)
+	}
+}
+
+func (c *testClient) login(name string) {
+	c.write(int32(server.Handshake), &protocol.Handshake{
+		ProtocolVersion: protocol.DefaultVersion.Protocol,
+		ServerAddress:   "localhost",
+		ServerPort:      25565,
+		NextState:       protocol.ClientStateLogin,
+	})
+	c.write(int32(server.LoginStart), &protocol.LoginStart{Name: name})
+}
+
+// encrypt answers the Encryption Request of the server, joining the session
+// service with profile when it is not nil.
+func (c *testClient) encrypt(sessions *authtest.Server, profile *auth.Profile) {
+	request := &protocol.EncryptionRequest{}
+	c.read(int32(client.EncryptionRequest), request)
+	c.answerEncryption(request, sessions, profile)
+}
+
+// answerEncryption answers the Encryption Request request, see encrypt.
+func (c *testClient) answerEncryption(request *protocol.EncryptionRequest, sessions *authtest.Server, profile *auth.Profile) {
+	publicKey, err := x509.ParsePKIXPublicKey(request.PublicKey)
+	if err != nil {
+		c.t.Fatalf("Unexpected error parsing the public key: '%v'", err)
+	}
+
+	secret := make([]byte, 16)
+	rand.Read(secret)
+	if profile != nil {
+		sessions.Join(*profile, auth.ServerHash(request.ServerID, secret, request.PublicKey))
+	}
+
+	encryptedSecret, _ := rsa.EncryptPKCS1v15(rand.Reader, publicKey.(*rsa.PublicKey), secret)
+	encryptedToken, _ := rsa.EncryptPKCS1v15(rand.Reader, publicKey.(*rsa.PublicKey), request.VerifyToken)
+	c.write(int32(server.EncryptionResponse), &protocol.EncryptionResponse{
+		SharedSecret: encryptedSecret,
+		VerifyToken:  encryptedToken,
+	})
+
+	block, _ := aes.NewCipher(secret)
+	c.r.SetCipher(protocol.NewCFB8Decrypter(block, secret))
+	c.w.SetCipher(protocol.NewCFB8Encrypter(block, secret))
+}
+
+func TestOnlineMode(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, KeySize)
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	sessions := authtest.NewServer()
+	defer sessions.Close()
+
+	config := Config{
+		CompressionThreshold: 256,
+		OnlineMode:           true,
+		PrivateKey:           key,
+		SessionVerifier:      sessions.SessionService(),
+	}
+
+	c := newTestClient(t, config)
+	defer c.conn.Close()
+	c.login("Notch")
+	c.encrypt(sessions, &auth.Profile{ID: uuid.Must(uuid.NewV4()), Name: "Notch"})
+
+	compression := &protocol.SetCompression{}
+	c.read(int32(client.SetCompression), compression)
+	if compression.Threshold != 256 {
+		t.Fatalf("Expected a compression threshold of 256 got %d", compression.Threshold)
+	}
+
+	// A player that did not join the session service is disconnected.
+	c = newTestClient(t, config)
+	defer c.conn.Close()
+	c.login("Notch")
+	c.encrypt(sessions, nil)
+
+	disconnect := &protocol.LoginDisconnect{}
+	c.read(int32(client.LoginDisconnect), disconnect)
+	if reason := disconnect.Reason.String(); reason != "Failed to verify username!" {
+		t.Fatalf("Expected '%s' got '%s'", "Failed to verify username!", reason)
+	}
+}
+
+func TestInvalidVerifyToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, KeySize)
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	c := newTestClient(t, Config{OnlineMode: true, PrivateKey: key})
+	defer c.conn.Close()
+	c.login("Notch")
+
+	request := &protocol.EncryptionRequest{}
+	c.read(int32(client.EncryptionRequest), request)
+	secret, _ := rsa.EncryptPKCS1v15(rand.Reader, &key.PublicKey, make([]byte, 16))
+	token, _ := rsa.EncryptPKCS1v15(rand.Reader, &key.PublicKey, []byte("nope"))
+	c.write(int32(server.EncryptionResponse), &protocol.EncryptionResponse{SharedSecret: secret, VerifyToken: token})
+
+	// The encryption is not enabled yet, the reason is readable.
+	disconnect := &protocol.LoginDisconnect{}
+	c.read(int32(client.LoginDisconnect), disconnect)
+	if reason := disconnect.Reason.String(); reason != "Unexpected packet" {
+		t.Fatalf("Expected '%s' got '%s'", "Unexpected packet", reason)
+	}
+	expected := ErrorCounts{BadState: 1}
+	if counts := c.client.ErrorCounts(); counts != expected {
+		t.Fatalf("Expected '%+v' got '%+v'", expected, counts)
+	}
+}
+
+// blockingVerifier waits for its context before failing with its error.
+type blockingVerifier chan error
+
+func (v blockingVerifier) HasJoined(ctx context.Context, username, serverHash string) (*auth.Profile, error) {
+	<-ctx.Done()
+	v <- ctx.Err()
+	return nil, ctx.Err()
+}
+
+func TestSessionVerifierCancel(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, KeySize)
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	verifier := make(blockingVerifier, 1)
+	c := newTestClient(t, Config{OnlineMode: true, PrivateKey: key, SessionVerifier: verifier})
+	defer c.conn.Close()
+	c.login("Notch")
+	c.encrypt(nil, nil)
+
+	// The request for a client that is closed is given up.
+	c.client.Disconnect(protocol.Text("Server closed"))
+	if err := <-verifier; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected '%v' got '%v'", context.Canceled, err)
+	}
+}
+
+func TestOfflineMode(t *testing.T) {
+	c := newTestClient(t, Config{CompressionThreshold: 64})
+	defer c.conn.Close()
+	c.login("Notch")
+
+	compression := &protocol.SetCompression{}
+	c.read(int32(client.SetCompression), compression)
+	if compression.Threshold != 64 {
+		t.Fatalf("Expected a compression threshold of 64 got %d", compression.Threshold)
+	}
+	c.r.SetCompression(64)
+
+	success := &protocol.LoginSuccess{}
+	c.read(int32(client.LoginSuccess), success)
+	expected := protocol.LoginSuccess{UUID: "b50ad385-829d-3141-a216-7e7d7539ba7f", Username: "Notch"}
+	if *success != expected {
+		t.Fatalf("Expected '%+v' got '%+v'", expected, *success)
+	}
+
+	c.read(int32(client.JoinGame), &protocol.JoinGame{})
+	c.read(int32(client.SpawnPosition), &protocol.SpawnPosition{})
+	c.read(int32(client.PlayerPositionAndLook), &protocol.ClientboundPlayerPositionAndLook{})
+}
+
+func TestDefaultCompression(t *testing.T) {
+	c := newTestClient(t, Config{})
+	defer c.conn.Close()
+	c.login("Notch")
+
+	compression := &protocol.SetCompression{}
+	c.read(int32(client.SetCompression), compression)
+	if compression.Threshold != DefaultCompressionThreshold {
+		t.Fatalf("Expected a compression threshold of %d got %d", DefaultCompressionThreshold, compression.Threshold)
+	}
+}
+
+func TestInvalidUsername(t *testing.T) {
+	c := newTestClient(t, Config{CompressionThreshold: -1})
+	defer c.conn.Close()
+	c.login("Not ch")
+
+	disconnect := &protocol.LoginDisconnect{}
+	c.read(int32(client.LoginDisconnect), disconnect)
+	if reason := disconnect.Reason.String(); reason != "Invalid username!" {
+		t.Fatalf("Expected '%s' got '%s'", "Invalid username!", reason)
+	}
+}
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/JDWardle/gocraft/protocol"
+)
+
+// LoginPlugin takes part in the login of the players on a login plugin
+// channel, for example to receive the profile of the player forwarded by a
+// proxy.
+// See https://wiki.vg/Protocol#Login_Plugin_Request for more info.
+type LoginPlugin interface {
+	// Request returns the data of the request sent when a player starts
+	// logging in.
+	Request(c *Client) ([]byte, error)
+	// Response handles the response of the player. It may send more requests
+	// with SendLoginPluginRequest. Setting the Profile of the client skips
+	// the authentication of the player.
+	Response(c *Client, resp PluginResponse) error
+}
+
+// PluginResponse is the response of a client to a login plugin request.
+type PluginResponse struct {
+	Channel protocol.Identifier
+	// Understood is false when the client does not know the channel, Data is
+	// then empty.
+	Understood bool
+	Data       []byte
+}
+
+// PluginResponseFunc handles the response to a login plugin request.
+type PluginResponseFunc func(c *Client, resp PluginResponse) error
+
+// pluginRequest is a login plugin request waiting for its response.
+type pluginRequest struct {
+	channel protocol.Identifier
+	f       PluginResponseFunc
+}
+
+// SendLoginPluginRequest sends data on the login plugin channel and calls f
+// with the response of the client. The login goes on once every request has
+// been answered. Requests can only be sent during the login. It is safe to
+// call from any goroutine, a request sent after the last response was
+// handled does not hold the login.
+func (c *Client) SendLoginPluginRequest(channel protocol.Identifier, data []byte, f PluginResponseFunc) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.State != protocol.ClientStateLogin {
+		return fmt.Errorf("login plugin request sent in state %d", c.State)
+	}
+
+	id := c.nextMessageID
+	c.nextMessageID++
+
+	err := c.send(&protocol.LoginPluginRequest{
+		MessageID: id,
+		Channel:   channel,
+		Data:      data,
+	})
+	if err != nil {
+		return err
+	}
+
+	if c.pluginRequests == nil {
+		c.pluginRequests = make(map[int32]pluginRequest)
+	}
+	c.pluginRequests[id] = pluginRequest{channel: channel, f: f}
+	return nil
+}
+
+// pluginsDone reports whether the client is logging in without login plugin
+// requests waiting for their response and is not authenticating yet.
+func (c *Client) pluginsDone() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.pluginRequests) == 0 && c.State == protocol.ClientStateLogin && !c.authenticating
+}
+
+// startLoginPlugins sends the requests of the login plugins of the config,
+// sorted by channel.
+func (c *Client) startLoginPlugins() error {
+	channels := make([]string, 0, len(c.Config.LoginPlugins))
+	for channel := range c.Config.LoginPlugins {
+		channels = append(channels, string(channel))
+	}
+	sort.Strings(channels)
+
+	for _, channel := range channels {
+		plugin := c.Config.LoginPlugins[protocol.Identifier(channel)]
+		data, err := plugin.Request(c)
+		if err != nil {
+			return err
+		}
+
+		if err := c.SendLoginPluginRequest(protocol.Identifier(channel), data, plugin.Response); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func LoginPluginResponseHandler(c *Client, p *protocol.LoginPluginResponse) error {
+	c.mu.Lock()
+	request, ok := c.pluginRequests[p.MessageID]
+	delete(c.pluginRequests, p.MessageID)
+	c.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: login plugin response %d without request", ErrBadState, p.MessageID)
+	}
+
+	err := request.f(c, PluginResponse{
+		Channel:    request.channel,
+		Understood: p.Successful,
+		Data:       p.Data,
+	})
+	if err != nil {
+		return err
+	}
+
+	if !c.pluginsDone() {
+		return nil
+	}
+	return c.authenticate()
+}
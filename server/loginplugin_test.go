@@ -0,0 +1,120 @@
+package server
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/JDWardle/gocraft/auth"
+	"github.com/JDWardle/gocraft/auth/authtest"
+	"github.com/JDWardle/gocraft/protocol"
+	"github.com/JDWardle/gocraft/protocol/client"
+	"github.com/JDWardle/gocraft/protocol/server"
+	"github.com/gofrs/uuid"
+)
+
+// forwardingPlugin authenticates players with the name sent in the response.
+type forwardingPlugin struct{}
+
+func (forwardingPlugin) Request(c *Client) ([]byte, error) {
+	return []byte("who"), nil
+}
+
+func (forwardingPlugin) Response(c *Client, resp PluginResponse) error {
+	if resp.Understood {
+		c.Profile = auth.OfflineProfile(string(resp.Data))
+	}
+	return nil
+}
+
+func TestLoginPlugins(t *testing.T) {
+	tests := map[bool]string{
+		true:  "jeb_",
+		false: "Notch",
+	}
+
+	for understood, expected := range tests {
+		c := newTestClient(t, Config{
+			CompressionThreshold: -1,
+			LoginPlugins:         map[protocol.Identifier]LoginPlugin{"test:forwarding": forwardingPlugin{}},
+		})
+		c.login("Notch")
+
+		request := &protocol.LoginPluginRequest{}
+		c.read(int32(client.LoginPluginRequest), request)
+		if request.Channel != "test:forwarding" || !bytes.Equal(request.Data, []byte("who")) {
+			t.Fatalf("Expected a request on 'test:forwarding' got '%+v'", request)
+		}
+
+		response := &protocol.LoginPluginResponse{MessageID: request.MessageID, Successful: understood}
+		if understood {
+			response.Data = []byte("jeb_")
+		}
+		c.write(int32(server.LoginPluginResponse), response)
+
+		success := &protocol.LoginSuccess{}
+		c.read(int32(client.LoginSuccess), success)
+		if success.Username != expected {
+			t.Fatalf("Expected '%s' to log in got '%s'", expected, success.Username)
+		}
+		c.conn.Close()
+	}
+}
+
+func TestLoginPluginAfterEncryption(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, KeySize)
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	sessions := authtest.NewServer()
+	defer sessions.Close()
+
+	c := newTestClient(t, Config{
+		CompressionThreshold: 256,
+		OnlineMode:           true,
+		PrivateKey:           key,
+		SessionVerifier:      sessions.SessionService(),
+	})
+	defer c.conn.Close()
+	c.login("Notch")
+
+	request := &protocol.EncryptionRequest{}
+	c.read(int32(client.EncryptionRequest), request)
+
+	// A request sent from another goroutine once the player is
+	// authenticating does not restart the authentication.
+	answered := make(chan struct{})
+	err = c.client.SendLoginPluginRequest("test:late", nil, func(c *Client, resp PluginResponse) error {
+		close(answered)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	pluginRequest := &protocol.LoginPluginRequest{}
+	c.read(int32(client.LoginPluginRequest), pluginRequest)
+	c.write(int32(server.LoginPluginResponse), &protocol.LoginPluginResponse{MessageID: pluginRequest.MessageID})
+	<-answered
+
+	c.answerEncryption(request, sessions, &auth.Profile{ID: uuid.Must(uuid.NewV4()), Name: "Notch"})
+	c.read(int32(client.SetCompression), &protocol.SetCompression{})
+}
+
+func TestLoginStartTwice(t *testing.T) {
+	c := newTestClient(t, Config{
+		CompressionThreshold: -1,
+		LoginPlugins:         map[protocol.Identifier]LoginPlugin{"test:forwarding": forwardingPlugin{}},
+	})
+	defer c.conn.Close()
+	c.login("Notch")
+	c.read(int32(client.LoginPluginRequest), &protocol.LoginPluginRequest{})
+
+	c.write(int32(server.LoginStart), &protocol.LoginStart{Name: "jeb_"})
+	disconnect := &protocol.LoginDisconnect{}
+	c.read(int32(client.LoginDisconnect), disconnect)
+	if reason := disconnect.Reason.String(); reason != "Unexpected packet" {
+		t.Fatalf("Expected '%s' got '%s'", "Unexpected packet", reason)
+	}
+}
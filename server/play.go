@@ -1,178 +1,174 @@
 package server
 
-import (
-	"bufio"
-	"errors"
-)
+import "github.com/JDWardle/gocraft/protocol"
 
-func TeleportConfirmHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
-}
+// The handlers of the Play state ignore the packets until the game is
+// implemented.
 
-func QueryBlockNBTHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func TeleportConfirmHandler(c *Client, p *protocol.TeleportConfirm) error {
+	return nil
 }
 
-func ChatMessageHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func QueryBlockNBTHandler(c *Client, p *protocol.QueryBlockNBT) error {
+	return nil
 }
 
-func ClientStatusHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func ChatMessageHandler(c *Client, p *protocol.ChatMessage) error {
+	return nil
 }
 
-func ClientSettingsHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func ClientStatusHandler(c *Client, p *protocol.ClientStatus) error {
+	return nil
 }
 
-func TabCompleteHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func ClientSettingsHandler(c *Client, p *protocol.ClientSettings) error {
+	return nil
 }
 
-func ConfirmTransactionHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func TabCompleteHandler(c *Client, p *protocol.TabComplete) error {
+	return nil
 }
 
-func EnchantItemHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func ConfirmTransactionHandler(c *Client, p *protocol.ConfirmTransaction) error {
+	return nil
 }
 
-func ClickWindowHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func EnchantItemHandler(c *Client, p *protocol.EnchantItem) error {
+	return nil
 }
 
-func CloseWindowHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func ClickWindowHandler(c *Client, p *protocol.ClickWindow) error {
+	return nil
 }
 
-func PluginMessageHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func CloseWindowHandler(c *Client, p *protocol.CloseWindow) error {
+	return nil
 }
 
-func EditBookHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func PluginMessageHandler(c *Client, p *protocol.PluginMessage) error {
+	return nil
 }
 
-func QueryEntityNBTHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func EditBookHandler(c *Client, p *protocol.EditBook) error {
+	return nil
 }
 
-func UseEntityHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func QueryEntityNBTHandler(c *Client, p *protocol.QueryEntityNBT) error {
+	return nil
 }
 
-func KeepAliveHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func UseEntityHandler(c *Client, p *protocol.UseEntity) error {
+	return nil
 }
 
-func PlayerHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func PlayerHandler(c *Client, p *protocol.Player) error {
+	return nil
 }
 
-func PlayerPositionHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func PlayerPositionHandler(c *Client, p *protocol.PlayerPosition) error {
+	return nil
 }
 
-func PlayerPositionAndLookHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func PlayerPositionAndLookHandler(c *Client, p *protocol.PlayerPositionAndLook) error {
+	return nil
 }
 
-func PlayerLookHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func PlayerLookHandler(c *Client, p *protocol.PlayerLook) error {
+	return nil
 }
 
-func VehicleMoveHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func VehicleMoveHandler(c *Client, p *protocol.VehicleMove) error {
+	return nil
 }
 
-func SteerBoatHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func SteerBoatHandler(c *Client, p *protocol.SteerBoat) error {
+	return nil
 }
 
-func PickItemHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func PickItemHandler(c *Client, p *protocol.PickItem) error {
+	return nil
 }
 
-func CraftRecipeRequestHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func CraftRecipeRequestHandler(c *Client, p *protocol.CraftRecipeRequest) error {
+	return nil
 }
 
-func PlayerAbilitiesHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func PlayerAbilitiesHandler(c *Client, p *protocol.PlayerAbilities) error {
+	return nil
 }
 
-func PlayerDiggingHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func PlayerDiggingHandler(c *Client, p *protocol.PlayerDigging) error {
+	return nil
 }
 
-func EntityActionHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func EntityActionHandler(c *Client, p *protocol.EntityAction) error {
+	return nil
 }
 
-func SteerVehicleHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func SteerVehicleHandler(c *Client, p *protocol.SteerVehicle) error {
+	return nil
 }
 
-func RecipeBookDataHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func RecipeBookDataHandler(c *Client, p *protocol.RecipeBookData) error {
+	return nil
 }
 
-func NameItemHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func NameItemHandler(c *Client, p *protocol.NameItem) error {
+	return nil
 }
 
-func ResourcePackStatusHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func ResourcePackStatusHandler(c *Client, p *protocol.ResourcePackStatus) error {
+	return nil
 }
 
-func AdvancementTabHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func AdvancementTabHandler(c *Client, p *protocol.AdvancementTab) error {
+	return nil
 }
 
-func SelectTradeHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func SelectTradeHandler(c *Client, p *protocol.SelectTrade) error {
+	return nil
 }
 
-func SetBeaconEffectHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func SetBeaconEffectHandler(c *Client, p *protocol.SetBeaconEffect) error {
+	return nil
 }
 
-func HeldItemChangeHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func HeldItemChangeHandler(c *Client, p *protocol.HeldItemChange) error {
+	return nil
 }
 
-func UpdateCommandBlockHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func UpdateCommandBlockHandler(c *Client, p *protocol.UpdateCommandBlock) error {
+	return nil
 }
 
-func UpdateCommandBlockMinecartHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func UpdateCommandBlockMinecartHandler(c *Client, p *protocol.UpdateCommandBlockMinecart) error {
+	return nil
 }
 
-func CreativeInventoryActionHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func CreativeInventoryActionHandler(c *Client, p *protocol.CreativeInventoryAction) error {
+	return nil
 }
 
-func UpdateStructureBlockHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func UpdateStructureBlockHandler(c *Client, p *protocol.UpdateStructureBlock) error {
+	return nil
 }
 
-func UpdateSignHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func UpdateSignHandler(c *Client, p *protocol.UpdateSign) error {
+	return nil
 }
 
-func AnimationHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func AnimationHandler(c *Client, p *protocol.Animation) error {
+	return nil
 }
 
-func SpectateHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func SpectateHandler(c *Client, p *protocol.Spectate) error {
+	return nil
 }
 
-func PlayerBlockPlacementHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func PlayerBlockPlacementHandler(c *Client, p *protocol.PlayerBlockPlacement) error {
+	return nil
 }
 
-func UseItemHandler(c *Client, r *bufio.Reader) error {
-	return errors.New("not implemented")
+func UseItemHandler(c *Client, p *protocol.UseItem) error {
+	return nil
 }
@@ -0,0 +1,176 @@
+package server
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/JDWardle/gocraft/capture"
+	"github.com/JDWardle/gocraft/protocol"
+)
+
+const (
+	// DefaultSendQueueSize is the number of packets queued for a client
+	// when the Config has no SendQueueSize.
+	DefaultSendQueueSize = 1024
+	// DefaultFlushInterval is the interval at which the queued packets are
+	// flushed when the Config has no FlushInterval. It is a game tick.
+	DefaultFlushInterval = 50 * time.Millisecond
+	// DefaultWriteTimeout is the time given to a write to the connection when
+	// the Config has no WriteTimeout.
+	DefaultWriteTimeout = 30 * time.Second
+)
+
+var (
+	// ErrSendQueueFull is returned by Send when the client does not read its
+	// packets fast enough. The client is disconnected.
+	ErrSendQueueFull = errors.New("server: send queue full")
+	// ErrClientClosed is returned by Send once the client is closed.
+	ErrClientClosed = errors.New("server: client closed")
+)
+
+// outbound is an item of the send queue of a client. Exactly one of its
+// fields is set.
+type outbound struct {
+	frame *protocol.Frame
+	// raw is written as is, for the legacy server list ping.
+	raw []byte
+	// apply changes the packet format of the following frames.
+	apply func(w *protocol.FrameWriter)
+}
+
+// Send queues the packet h to be sent to the client. The ID of the packet is
+// looked up by name in the packets of the client's version for its current
+// state, see protocol.PacketName, and h is converted to the layout of that
+// version when it differs.
+// It is safe to call from any goroutine. The queued packets are sent in
+// order by the writer of the client, which flushes them every
+// Config.FlushInterval.
+//
+// A client with a full queue is disconnected and ErrSendQueueFull returned.
+func (c *Client) Send(h protocol.Handler) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.send(h)
+}
+
+// send queues the packet h. c.mu must be held.
+func (c *Client) send(h protocol.Handler) error {
+	// The packet of the client's version with the same name, converted when
+	// its layout differs.
+	id, ok := c.Version.ClientPackets.IDByName(c.State, protocol.PacketName(h))
+	if !ok {
+		return fmt.Errorf("packet %T is not sent in state %d of %s", h, c.State, c.Version)
+	}
+	if _, p := c.Version.ClientPackets.GetPacket(c.State, id); reflect.TypeOf(p) != reflect.TypeOf(h) {
+		protocol.ConvertPacket(p, h)
+		h = p
+	}
+
+	data, err := h.Encode()
+	if err != nil {
+		return err
+	}
+
+	if err := c.enqueue(outbound{frame: &protocol.Frame{ID: id, Data: data}}); err != nil {
+		return err
+	}
+	c.record(capture.Clientbound, c.State, id, data)
+	return nil
+}
+
+// enqueue adds o to the send queue without blocking, starting the writer of
+// the client on the first call. c.mu must be held.
+func (c *Client) enqueue(o outbound) error {
+	select {
+	case <-c.closed:
+		return ErrClientClosed
+	default:
+	}
+
+	if c.queue == nil {
+		c.queue = make(chan outbound, c.Config.sendQueueSize())
+		c.written = make(chan struct{})
+		go c.writeLoop()
+	}
+
+	select {
+	case c.queue <- o:
+		return nil
+	default:
+		// The client does not keep up, sending it a reason would only wait
+		// for the queue.
+		c.Logger().Warn("send queue full, disconnecting the client", "size", cap(c.queue))
+		c.conn.Close()
+		c.close()
+		return ErrSendQueueFull
+	}
+}
+
+// writeLoop writes the queued packets to the connection until the client is
+// closed. The packets still queued are then flushed before the connection is
+// closed.
+func (c *Client) writeLoop() {
+	defer close(c.written)
+	defer c.conn.Close()
+
+	w := bufio.NewWriter(&deadlineWriter{c})
+	frames := protocol.NewFrameWriter(w)
+
+	ticker := time.NewTicker(c.Config.flushInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case o := <-c.queue:
+			if err := c.write(frames, w, o); err != nil {
+				c.Logger().Debug("write failed", "err", err)
+				return
+			}
+		case <-ticker.C:
+			if err := w.Flush(); err != nil {
+				c.Logger().Debug("write failed", "err", err)
+				return
+			}
+		case <-c.closed:
+			for {
+				select {
+				case o := <-c.queue:
+					if err := c.write(frames, w, o); err != nil {
+						return
+					}
+				default:
+					w.Flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (c *Client) write(frames *protocol.FrameWriter, w *bufio.Writer, o outbound) error {
+	switch {
+	case o.frame != nil:
+		c.trace("packet sent", "id", o.frame.ID, "length", protocol.SizeVarInt(uint32(o.frame.ID))+len(o.frame.Data))
+		return frames.WriteFrame(o.frame.ID, o.frame.Data)
+	case o.raw != nil:
+		_, err := w.Write(o.raw)
+		return err
+	}
+	o.apply(frames)
+	return nil
+}
+
+// deadlineWriter writes to the connection of a client with a deadline of
+// Config.WriteTimeout.
+type deadlineWriter struct {
+	c *Client
+}
+
+func (w *deadlineWriter) Write(b []byte) (int, error) {
+	w.c.conn.SetWriteDeadline(time.Now().Add(w.c.Config.writeTimeout()))
+	return w.c.conn.Write(b)
+}
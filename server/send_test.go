@@ -0,0 +1,119 @@
+package server
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JDWardle/gocraft/protocol"
+	"github.com/JDWardle/gocraft/protocol/client"
+)
+
+func TestSend(t *testing.T) {
+	conn, serverConn := net.Pipe()
+	defer conn.Close()
+
+	c := NewClient(1, serverConn)
+	c.Config = Config{FlushInterval: time.Millisecond}
+	c.State = protocol.ClientStateStatus
+	defer c.Close()
+
+	// Each goroutine sends its packets in order, with its index in the
+	// high bits of the payload.
+	const senders, packets = 4, 50
+	var wg sync.WaitGroup
+	for i := 0; i < senders; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < packets; j++ {
+				if err := c.Send(&protocol.Pong{Payload: int64(i<<32 | j)}); err != nil {
+					t.Errorf("Unexpected error: '%v'", err)
+				}
+			}
+		}(i)
+	}
+
+	tc := wrapTestClient(t, conn)
+	next := make([]int64, senders)
+	for n := 0; n < senders*packets; n++ {
+		pong := &protocol.Pong{}
+		tc.read(int32(client.Pong), pong)
+		i, j := pong.Payload>>32, pong.Payload&0xffffffff
+		if j != next[i] {
+			t.Fatalf("Expected packet %d of sender %d got %d", next[i], i, j)
+		}
+		next[i]++
+	}
+	wg.Wait()
+}
+
+func TestSendVersion(t *testing.T) {
+	conn, serverConn := net.Pipe()
+	defer conn.Close()
+
+	c := NewClient(1, serverConn)
+	c.Config = Config{FlushInterval: time.Millisecond}
+	c.Version = testVersion
+	c.State = protocol.ClientStatePlay
+	defer c.Close()
+
+	if err := c.Send(&protocol.ClientboundKeepAlive{KeepAliveID: 7}); err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	if err := c.Send(&protocol.JoinGame{}); err == nil {
+		t.Fatalf("Expected an error for a packet missing from the version")
+	}
+
+	keepAlive := &ClientboundKeepAlive340{}
+	wrapTestClient(t, conn).read(0x1F, keepAlive)
+	if keepAlive.KeepAliveID != 7 {
+		t.Fatalf("Expected keep alive ID 7 got %d", keepAlive.KeepAliveID)
+	}
+}
+
+func TestSendQueueFull(t *testing.T) {
+	// Nothing is read from conn so the writes to serverConn block.
+	conn, serverConn := net.Pipe()
+	defer conn.Close()
+
+	c := NewClient(1, serverConn)
+	c.Config = Config{SendQueueSize: 2, FlushInterval: time.Millisecond, WriteTimeout: time.Minute}
+	c.State = protocol.ClientStateStatus
+
+	var err error
+	for i := 0; i < 100 && err == nil; i++ {
+		err = c.Send(&protocol.Pong{Payload: int64(i)})
+		time.Sleep(time.Millisecond)
+	}
+	if err != ErrSendQueueFull {
+		t.Fatalf("Expected '%v' got '%v'", ErrSendQueueFull, err)
+	}
+
+	if err := c.Send(&protocol.Pong{}); err != ErrClientClosed {
+		t.Fatalf("Expected '%v' got '%v'", ErrClientClosed, err)
+	}
+	if _, err := serverConn.Read(make([]byte, 1)); err == nil {
+		t.Fatalf("Expected the connection to be closed")
+	}
+}
+
+func TestWriteTimeout(t *testing.T) {
+	conn, serverConn := net.Pipe()
+	defer conn.Close()
+
+	c := NewClient(1, serverConn)
+	c.Config = Config{FlushInterval: time.Millisecond, WriteTimeout: 10 * time.Millisecond}
+	c.State = protocol.ClientStateStatus
+
+	if err := c.Send(&protocol.Pong{}); err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	select {
+	case <-c.written:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the write to time out")
+	}
+}
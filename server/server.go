@@ -0,0 +1,321 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/JDWardle/gocraft/auth"
+	"github.com/JDWardle/gocraft/protocol"
+	"github.com/gofrs/uuid"
+)
+
+// ErrServerClosed is returned by the Serve and ListenAndServe methods of a
+// Server after a call to Shutdown.
+var ErrServerClosed = errors.New("server: server closed")
+
+// ShutdownReason is the reason sent to the players disconnected by Shutdown.
+var ShutdownReason = protocol.Text("Server closed")
+
+// Server accepts the connections of the clients and keeps track of them. The
+// zero value is a server listening on :25565 with the DefaultConfig.
+type Server struct {
+	// Addr is the TCP address to listen on, ":25565" when empty.
+	Addr string
+	// Config is the configuration of the clients, DefaultConfig when nil.
+	// When it has no StatusProvider the DefaultStatus is shown with the
+	// players of the server.
+	Config *Config
+	// Handlers handles the packets of the clients, DefaultHandlers when nil.
+	Handlers *Mux
+
+	// OnConnect is called with each new client before its packets are
+	// handled and OnDisconnect once its connection is closed. They are
+	// called from the goroutine of the client.
+	OnConnect    func(c *Client)
+	OnDisconnect func(c *Client)
+
+	mu        sync.RWMutex
+	listeners map[net.Listener]struct{}
+	closed    bool
+	nextID    int
+	clients   map[int]*Client
+	// Players by UUID and by lower case name, added once they are logged in.
+	byUUID map[uuid.UUID]*Client
+	byName map[string]*Client
+
+	wg     sync.WaitGroup
+	errors errorCounters
+}
+
+// ListenAndServe listens on the TCP address s.Addr and serves the clients
+// until ctx is done or Shutdown is called.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	addr := s.Addr
+	if addr == "" {
+		addr = ":25565"
+	}
+
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, l)
+}
+
+// Serve accepts the connections of l and handles each client in its own
+// goroutine until ctx is done or Shutdown is called. l is closed when Serve
+// returns. The clients still connected when ctx is done are not disconnected,
+// call Shutdown to disconnect them.
+func (s *Server) Serve(ctx context.Context, l net.Listener) error {
+	if !s.trackListener(l) {
+		l.Close()
+		return ErrServerClosed
+	}
+	defer s.untrackListener(l)
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			l.Close()
+		case <-done:
+		}
+	}()
+
+	config := s.config()
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if s.isClosed() {
+				return ErrServerClosed
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+
+		c := s.newClient(conn, config)
+		if c == nil {
+			conn.Close()
+			return ErrServerClosed
+		}
+		go s.handle(c)
+	}
+}
+
+// Shutdown stops accepting connections, disconnects every client with the
+// ShutdownReason and waits for their handlers to return. It returns the
+// error of ctx when it is done before.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.closed = true
+	for l := range s.listeners {
+		l.Close()
+	}
+	clients := make([]*Client, 0, len(s.clients))
+	for _, c := range s.clients {
+		clients = append(clients, c)
+	}
+	s.mu.Unlock()
+
+	for _, c := range clients {
+		c.Disconnect(ShutdownReason)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Client returns the connected client with the ID id.
+func (s *Server) Client(id int) (*Client, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, ok := s.clients[id]
+	return c, ok
+}
+
+// ClientByUUID returns the logged in player with the UUID id.
+func (s *Server) ClientByUUID(id uuid.UUID) (*Client, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, ok := s.byUUID[id]
+	return c, ok
+}
+
+// ClientByName returns the logged in player named name, ignoring case.
+func (s *Server) ClientByName(name string) (*Client, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, ok := s.byName[strings.ToLower(name)]
+	return c, ok
+}
+
+// Clients returns the connected clients ordered by ID, including the ones
+// that are not logged in.
+func (s *Server) Clients() []*Client {
+	s.mu.RLock()
+	clients := make([]*Client, 0, len(s.clients))
+	for _, c := range s.clients {
+		clients = append(clients, c)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(clients, func(i, j int) bool { return clients[i].ID < clients[j].ID })
+	return clients
+}
+
+// Players returns the profiles of the logged in players ordered by the ID of
+// their client.
+func (s *Server) Players() []*auth.Profile {
+	s.mu.RLock()
+	clients := make([]*Client, 0, len(s.byUUID))
+	for _, c := range s.byUUID {
+		clients = append(clients, c)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(clients, func(i, j int) bool { return clients[i].ID < clients[j].ID })
+	players := make([]*auth.Profile, len(clients))
+	for i, c := range clients {
+		players[i] = c.Profile
+	}
+	return players
+}
+
+// config returns the Config of the clients.
+func (s *Server) config() Config {
+	config := DefaultConfig
+	if s.Config != nil {
+		config = *s.Config
+	}
+
+	if config.StatusProvider == nil {
+		if status, ok := DefaultStatus.(*BasicStatus); ok && status.Players == nil {
+			withPlayers := *status
+			withPlayers.Players = s.Players
+			config.StatusProvider = &withPlayers
+		}
+	}
+	return config
+}
+
+func (s *Server) trackListener(l net.Listener) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return false
+	}
+	if s.listeners == nil {
+		s.listeners = make(map[net.Listener]struct{})
+	}
+	s.listeners[l] = struct{}{}
+	return true
+}
+
+func (s *Server) untrackListener(l net.Listener) {
+	s.mu.Lock()
+	delete(s.listeners, l)
+	s.mu.Unlock()
+
+	l.Close()
+}
+
+func (s *Server) isClosed() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.closed
+}
+
+// newClient registers a new client for conn. It returns nil when the server
+// is shut down.
+func (s *Server) newClient(conn net.Conn, config Config) *Client {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	if s.clients == nil {
+		s.clients = make(map[int]*Client)
+		s.byUUID = make(map[uuid.UUID]*Client)
+		s.byName = make(map[string]*Client)
+	}
+
+	s.nextID++
+	c := NewClient(s.nextID, conn)
+	c.Config = config
+	if s.Handlers != nil {
+		c.Handlers = s.Handlers
+	}
+	c.server = s
+	s.clients[c.ID] = c
+	// Added under the lock so Shutdown waits for every client it sees.
+	s.wg.Add(1)
+	return c
+}
+
+// handle handles the packets of c until its connection is closed.
+func (s *Server) handle(c *Client) {
+	defer s.wg.Done()
+
+	c.Logger().Info("client connected")
+	if s.OnConnect != nil {
+		s.OnConnect(c)
+	}
+
+	c.HandleMessages()
+
+	s.mu.Lock()
+	delete(s.clients, c.ID)
+	if c.Profile != nil && s.byUUID[c.Profile.ID] == c {
+		delete(s.byUUID, c.Profile.ID)
+		delete(s.byName, strings.ToLower(c.Profile.Name))
+	}
+	s.mu.Unlock()
+
+	if s.OnDisconnect != nil {
+		s.OnDisconnect(c)
+	}
+}
+
+// addPlayer registers the logged in player of c. It returns the client
+// already logged in with the same UUID or name, if any, which is replaced.
+func (s *Server) addPlayer(c *Client) *Client {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := strings.ToLower(c.Profile.Name)
+	old, ok := s.byUUID[c.Profile.ID]
+	if !ok {
+		old = s.byName[name]
+	}
+	if old != nil {
+		delete(s.byUUID, old.Profile.ID)
+		delete(s.byName, strings.ToLower(old.Profile.Name))
+	}
+
+	s.byUUID[c.Profile.ID] = c
+	s.byName[name] = c
+	return old
+}
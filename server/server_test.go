@@ -0,0 +1,124 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/JDWardle/gocraft/auth"
+	"github.com/JDWardle/gocraft/protocol"
+	"github.com/JDWardle/gocraft/protocol/client"
+)
+
+// startTestServer serves s on a local port. The error of Serve is sent on the
+// returned channel.
+func startTestServer(t *testing.T, s *Server) (string, <-chan error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	errs := make(chan error, 1)
+	go func() { errs <- s.Serve(context.Background(), l) }()
+	return l.Addr().String(), errs
+}
+
+// join logs a player in offline mode without compression and reads the
+// packets of the login.
+func join(t *testing.T, addr, name string) *testClient {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	c := wrapTestClient(t, conn)
+	c.login(name)
+	c.read(int32(client.LoginSuccess), &protocol.LoginSuccess{})
+	c.read(int32(client.JoinGame), &protocol.JoinGame{})
+	c.read(int32(client.SpawnPosition), &protocol.SpawnPosition{})
+	c.read(int32(client.PlayerPositionAndLook), &protocol.ClientboundPlayerPositionAndLook{})
+	return c
+}
+
+func TestServer(t *testing.T) {
+	connected := make(chan *Client, 1)
+	disconnected := make(chan *Client, 1)
+	s := &Server{
+		Config:       &Config{CompressionThreshold: -1},
+		OnConnect:    func(c *Client) { connected <- c },
+		OnDisconnect: func(c *Client) { disconnected <- c },
+	}
+	addr, errs := startTestServer(t, s)
+
+	c := join(t, addr, "Notch")
+	defer c.conn.Close()
+	connectedClient := <-connected
+
+	player, ok := s.ClientByName("notch")
+	if !ok || player != connectedClient {
+		t.Fatalf("Expected '%p' got '%p'", connectedClient, player)
+	}
+	if player, ok := s.ClientByUUID(auth.OfflineUUID("Notch")); !ok || player != connectedClient {
+		t.Fatalf("Expected '%p' got '%p'", connectedClient, player)
+	}
+	if player, ok := s.Client(connectedClient.ID); !ok || player != connectedClient {
+		t.Fatalf("Expected '%p' got '%p'", connectedClient, player)
+	}
+	if _, ok := s.ClientByName("jeb_"); ok {
+		t.Fatalf("Expected no player named 'jeb_'")
+	}
+
+	status, err := player.status()
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	if status.Players.Online != 1 || status.Players.Sample[0].Name != "Notch" {
+		t.Fatalf("Expected 'Notch' to be online got '%+v'", status.Players)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.Shutdown(ctx); err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	disconnect := &protocol.Disconnect{}
+	c.read(int32(client.Disconnect), disconnect)
+	if reason := disconnect.Reason.String(); reason != ShutdownReason.String() {
+		t.Fatalf("Expected '%s' got '%s'", ShutdownReason, reason)
+	}
+
+	if err := <-errs; err != ErrServerClosed {
+		t.Fatalf("Expected '%v' got '%v'", ErrServerClosed, err)
+	}
+	if c := <-disconnected; c != connectedClient {
+		t.Fatalf("Expected '%p' got '%p'", connectedClient, c)
+	}
+	if n := len(s.Clients()); n != 0 {
+		t.Fatalf("Expected no clients got %d", n)
+	}
+}
+
+func TestServerDuplicateLogin(t *testing.T) {
+	s := &Server{Config: &Config{CompressionThreshold: -1}}
+	addr, errs := startTestServer(t, s)
+
+	first := join(t, addr, "Notch")
+	defer first.conn.Close()
+	second := join(t, addr, "notch")
+	defer second.conn.Close()
+
+	disconnect := &protocol.Disconnect{}
+	first.read(int32(client.Disconnect), disconnect)
+	if reason := disconnect.Reason.String(); reason != "You logged in from another location" {
+		t.Fatalf("Expected '%s' got '%s'", "You logged in from another location", reason)
+	}
+
+	if players := s.Players(); len(players) != 1 || players[0].Name != "notch" {
+		t.Fatalf("Expected 'notch' to be the only player got '%+v'", players)
+	}
+
+	s.Shutdown(context.Background())
+	<-errs
+}
@@ -1,29 +1,174 @@
 package server
 
 import (
-	"bufio"
 	"bytes"
-	"encoding/binary"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image/png"
+	"net"
+	"os"
 
+	"github.com/JDWardle/gocraft/auth"
 	"github.com/JDWardle/gocraft/protocol"
 )
 
-func StatusRequestHandler(c *Client, r *bufio.Reader) error {
-	// Nothing really happens with this packet.
-	return nil
+// Status is the status of the server shown in the server list.
+// See https://wiki.vg/Server_List_Ping#Response for more info.
+type Status struct {
+	Version     StatusVersion `json:"version"`
+	Players     StatusPlayers `json:"players"`
+	Description protocol.Chat `json:"description"`
+	// Favicon is a 64x64 PNG image as a data URI, see LoadFavicon.
+	Favicon string `json:"favicon,omitempty"`
 }
 
-func PingHandler(c *Client, r *bufio.Reader) error {
-	var pingPayload int64
-	if err := binary.Read(r, binary.BigEndian, &pingPayload); err != nil {
+// StatusVersion is the version of the server. Clients of another protocol
+// version show the server as incompatible.
+type StatusVersion struct {
+	Name     string `json:"name"`
+	Protocol int32  `json:"protocol"`
+}
+
+// StatusPlayers are the players of the server. The names of the Sample are
+// shown when hovering the player count.
+type StatusPlayers struct {
+	Max    int            `json:"max"`
+	Online int            `json:"online"`
+	Sample []StatusPlayer `json:"sample"`
+}
+
+// StatusPlayer is a player of the StatusPlayers sample.
+type StatusPlayer struct {
+	Name string `json:"name"`
+	ID   string `json:"id"`
+}
+
+// StatusRequest describes the client requesting the status of the server.
+type StatusRequest struct {
+	RemoteAddr net.Addr
+	// ServerAddress and ServerPort are the address the client connected to,
+	// as sent in its Handshake.
+	ServerAddress string
+	ServerPort    uint16
+	// ProtocolVersion is the protocol version of the client and Version the
+	// version the server uses to talk to it. Clients of an unsupported
+	// version use the protocol.DefaultVersion.
+	ProtocolVersion int32
+	Version         *protocol.Version
+}
+
+// StatusProvider builds the status of the server for each status request.
+type StatusProvider interface {
+	Status(req *StatusRequest) (*Status, error)
+}
+
+// MaxStatusSample is the maximum number of players in the sample of the
+// Status built by BasicStatus, as done by vanilla servers.
+const MaxStatusSample = 12
+
+// BasicStatus is a StatusProvider with a fixed description and favicon.
+type BasicStatus struct {
+	Description protocol.Chat
+	MaxPlayers  int
+	Favicon     string
+
+	// Players returns the online players. There are none when it is nil.
+	Players func() []*auth.Profile
+	// Customize, when not nil, is called with each status before it is sent,
+	// for example to show another description depending on the address the
+	// client connected to.
+	Customize func(req *StatusRequest, s *Status) error
+}
+
+// Status implements the StatusProvider interface.
+func (b *BasicStatus) Status(req *StatusRequest) (*Status, error) {
+	s := &Status{
+		Version: StatusVersion{
+			Name:     req.Version.Name,
+			Protocol: req.Version.Protocol,
+		},
+		Players: StatusPlayers{
+			Max:    b.MaxPlayers,
+			Sample: []StatusPlayer{},
+		},
+		Description: b.Description,
+		Favicon:     b.Favicon,
+	}
+
+	if b.Players != nil {
+		players := b.Players()
+		s.Players.Online = len(players)
+		for i := 0; i < len(players) && i < MaxStatusSample; i++ {
+			s.Players.Sample = append(s.Players.Sample, StatusPlayer{
+				Name: players[i].Name,
+				ID:   players[i].ID.String(),
+			})
+		}
+	}
+
+	if b.Customize != nil {
+		if err := b.Customize(req, s); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// DefaultStatus is the StatusProvider used when the Config has none.
+var DefaultStatus StatusProvider = &BasicStatus{
+	Description: protocol.Text("Hello Minecraft from Go!"),
+	MaxPlayers:  20,
+}
+
+// LoadFavicon reads the PNG image of the file name and returns it as a data
+// URI to be used as the Favicon of a Status. The image must be 64x64 pixels.
+func LoadFavicon(name string) (string, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return "", err
+	}
+
+	config, err := png.DecodeConfig(bytes.NewReader(b))
+	if err != nil {
+		return "", fmt.Errorf("favicon %s: %v", name, err)
+	}
+	if config.Width != 64 || config.Height != 64 {
+		return "", fmt.Errorf("favicon %s: image is %dx%d instead of 64x64", name, config.Width, config.Height)
+	}
+
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(b), nil
+}
+
+// status returns the status of the server for the client.
+func (c *Client) status() (*Status, error) {
+	provider := c.Config.StatusProvider
+	if provider == nil {
+		provider = DefaultStatus
+	}
+
+	return provider.Status(&StatusRequest{
+		RemoteAddr:      c.conn.RemoteAddr(),
+		ServerAddress:   c.handshake.ServerAddress,
+		ServerPort:      c.handshake.ServerPort,
+		ProtocolVersion: c.handshake.ProtocolVersion,
+		Version:         c.Version,
+	})
+}
+
+func StatusRequestHandler(c *Client, p *protocol.StatusRequest) error {
+	s, err := c.status()
+	if err != nil {
 		return err
 	}
 
-	packet := bytes.NewBuffer(protocol.VarInt(1))
-	if err := binary.Write(packet, binary.BigEndian, pingPayload); err != nil {
+	b, err := json.Marshal(s)
+	if err != nil {
 		return err
 	}
-	c.conn.Write(append(protocol.VarInt(int32(len(packet.Bytes()))), packet.Bytes()...))
+	return c.Send(&protocol.StatusResponse{JSONResponse: string(b)})
+}
 
-	return nil
+func PingHandler(c *Client, ping *protocol.Ping) error {
+	return c.Send(&protocol.Pong{Payload: ping.Payload})
 }
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JDWardle/gocraft/auth"
+	"github.com/JDWardle/gocraft/protocol"
+	"github.com/JDWardle/gocraft/protocol/client"
+	"github.com/JDWardle/gocraft/protocol/server"
+)
+
+func TestStatus(t *testing.T) {
+	players := []*auth.Profile{auth.OfflineProfile("Notch"), auth.OfflineProfile("jeb_")}
+	c := newTestClient(t, Config{StatusProvider: &BasicStatus{
+		Description: protocol.Text("A Minecraft Server"),
+		MaxPlayers:  20,
+		Players:     func() []*auth.Profile { return players },
+		Customize: func(req *StatusRequest, s *Status) error {
+			if req.ServerAddress == "creative.localhost" {
+				s.Description = protocol.Text("Creative")
+			}
+			return nil
+		},
+	}})
+	defer c.conn.Close()
+
+	c.write(int32(server.Handshake), &protocol.Handshake{
+		ProtocolVersion: protocol.DefaultVersion.Protocol,
+		ServerAddress:   "creative.localhost",
+		ServerPort:      25565,
+		NextState:       protocol.ClientStateStatus,
+	})
+	c.write(int32(server.Request), &protocol.StatusRequest{})
+
+	response := &protocol.StatusResponse{}
+	c.read(int32(client.Response), response)
+
+	var status Status
+	if err := json.Unmarshal([]byte(response.JSONResponse), &status); err != nil {
+		t.Fatalf("Unexpected error decoding '%s': '%v'", response.JSONResponse, err)
+	}
+
+	expected := Status{
+		Version: StatusVersion{Name: protocol.DefaultVersion.Name, Protocol: protocol.DefaultVersion.Protocol},
+		Players: StatusPlayers{Max: 20, Online: 2, Sample: []StatusPlayer{
+			{Name: "Notch", ID: "b50ad385-829d-3141-a216-7e7d7539ba7f"},
+			{Name: "jeb_", ID: "a762f560-4fce-3236-812a-b80efff0b62b"},
+		}},
+		Description: protocol.Text("Creative"),
+	}
+	if !reflect.DeepEqual(status, expected) {
+		t.Fatalf("Expected '%+v' got '%+v'", expected, status)
+	}
+}
+
+func TestLoadFavicon(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := map[int]bool{64: true, 32: false}
+	for size, valid := range tests {
+		name := filepath.Join(dir, "server-icon.png")
+		f, _ := os.Create(name)
+		png.Encode(f, image.NewRGBA(image.Rect(0, 0, size, size)))
+		f.Close()
+
+		favicon, err := LoadFavicon(name)
+		if valid && (err != nil || !strings.HasPrefix(favicon, "data:image/png;base64,")) {
+			t.Fatalf("Expected a %dx%d favicon to be loaded got '%s' '%v'", size, size, favicon, err)
+		}
+		if !valid && err == nil {
+			t.Fatalf("Expected an error loading a %dx%d favicon", size, size)
+		}
+	}
+}
+
+func TestLegacyServerListPing(t *testing.T) {
+	pingHost := append([]byte{0xFE, 0x01, 0xFA}, protocol.LegacyString("MC|PingHost")...)
+	pingHost = append(pingHost, 0x00, 0x19, 0x4a)
+	pingHost = append(pingHost, protocol.LegacyString("localhost")...)
+	pingHost = append(pingHost, 0x00, 0x00, 0x63, 0xdd)
+
+	legacy := "§1\x00127\x00" + protocol.DefaultVersion.Name + "\x00Hello Minecraft from Go!\x000\x0020"
+	tests := map[string]struct {
+		ping     []byte
+		expected string
+	}{
+		"beta": {[]byte{0xFE}, "Hello Minecraft from Go!§0§20"},
+		"1.4":  {[]byte{0xFE, 0x01}, legacy},
+		"1.6":  {pingHost, legacy},
+	}
+
+	for name, test := range tests {
+		c := newTestClient(t, Config{})
+		if _, err := c.conn.Write(test.ping); err != nil {
+			t.Fatalf("Unexpected error writing the %s ping: '%v'", name, err)
+		}
+
+		r := bufio.NewReader(c.conn)
+		if b, err := r.ReadByte(); err != nil || b != 0xFF {
+			t.Fatalf("Expected the %s response to start with 0xff got %#02x '%v'", name, b, err)
+		}
+		response, err := protocol.ReadLegacyString(r, 256)
+		if err != nil {
+			t.Fatalf("Unexpected error reading the %s response: '%v'", name, err)
+		}
+		if response != test.expected {
+			t.Fatalf("Expected the %s response to be '%q' got '%q'", name, test.expected, response)
+		}
+		c.conn.Close()
+	}
+}